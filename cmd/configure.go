@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"tm/tm/v2/context"
+	"tm/tm/v2/initialize"
+	"tm/tm/v2/ux"
+)
+
+var configureCmd = &cobra.Command{
+	Use:   "configure",
+	Short: "Reapply the config.toml and app.toml settings of one or more node(s) or testnet(s)",
+	Run: func(cmd *cobra.Command, args []string) {
+
+		// Load chain config
+		ctx := context.New(args)
+
+		// Execute configure
+		results := initialize.Configure(ctx)
+		ux.Summary(results)
+		ux.JSON(results)
+		ux.ExitOnFailure(results)
+	},
+}
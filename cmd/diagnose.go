@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"os"
+	"tm/tm/v2/context"
+	"tm/tm/v2/startstop"
+	"tm/tm/v2/ux"
+)
+
+var diagnoseCmd = &cobra.Command{
+	Use:   "diagnose",
+	Short: "Find out why one or more testnet(s) stall",
+	Run: func(cmd *cobra.Command, args []string) {
+
+		// Load chain config
+		ctx := context.New(args)
+
+		// Execute diagnose
+		if !startstop.Diagnose(ctx) {
+			os.Exit(ux.ExitCodeFailure)
+		}
+	},
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"time"
+	"tm/tm/v2/context"
+	"tm/tm/v2/startstop"
+	"tm/tm/v2/ux"
+)
+
+var (
+	flagWait          bool
+	flagHealthTimeout time.Duration
+	flagBlockWindow   time.Duration
+	flagRelayerWindow time.Duration
+)
+
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Check the health of one or more node(s), testnet(s) or relayer(s)",
+	Run: func(cmd *cobra.Command, args []string) {
+
+		// Load chain config
+		ctx := context.New(args)
+
+		// Execute health
+		results := startstop.Health(ctx)
+		ux.Summary(results)
+		ux.JSON(results)
+		ux.ExitOnFailure(results)
+	},
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"time"
+	"tm/tm/v2/context"
+	"tm/tm/v2/ibc"
+	"tm/tm/v2/ux"
+)
+
+var (
+	flagPort            string
+	flagChannelVersion  string
+	flagReceiver        string
+	flagTransferTimeout time.Duration
+)
+
+var ibcCmd = &cobra.Command{
+	Use:   "ibc",
+	Short: "Manage IBC connections between testnets",
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help()
+	},
+}
+
+var ibcConnectCmd = &cobra.Command{
+	Use:   "connect <chain> <chain>",
+	Short: "Create clients, a connection and a channel between two testnets",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		// Load chain config
+		ctx := context.New(args)
+
+		// Execute connect
+		result := ibc.Connect(ctx, args[0], args[1], viper.GetString("port"), viper.GetString("channel-version"))
+		ux.JSON(result)
+		ux.ExitOnFailure([]ux.Result{result})
+	},
+}
+
+var ibcTransferCmd = &cobra.Command{
+	Use:   "transfer <source chain> <destination chain> <wallet> <amount>",
+	Short: "Send an ICS-20 transfer between two testnets and track the packet",
+	Args:  cobra.ExactArgs(4),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		// Load chain config
+		ctx := context.New(args[:2])
+
+		// Execute transfer
+		result := ibc.Transfer(ctx, args[0], args[1], args[2], viper.GetString("receiver"), args[3], viper.GetString("port"), viper.GetDuration("transfer-timeout"))
+		ux.JSON(result)
+		ux.ExitOnFailure([]ux.Result{result})
+	},
+}
@@ -6,6 +6,7 @@ import (
 	"tm/tm/v2/context"
 	"tm/tm/v2/initialize"
 	"tm/tm/v2/tmconfig"
+	"tm/tm/v2/ux"
 )
 
 var initCmd = &cobra.Command{
@@ -23,6 +24,9 @@ var initCmd = &cobra.Command{
 		ctx := context.New(args)
 
 		// Initialize chain config
-		initialize.Initialize(ctx)
+		results := initialize.Initialize(ctx)
+		ux.Summary(results)
+		ux.JSON(results)
+		ux.ExitOnFailure(results)
 	},
 }
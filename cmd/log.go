@@ -7,13 +7,19 @@ import (
 )
 
 var (
-	flagf bool
-	flagF bool
+	flagf          bool
+	flagF          bool
+	flagSortByTime bool
+	flagLevel      string
+	flagModule     string
+	flagGrep       string
+	flagSince      string
+	flagRun        int
 )
 
 var logCmd = &cobra.Command{
 	Use:   "log",
-	Short: "Get the log of one or more node(s) or testnet(s)",
+	Short: "Get the log of one or more node(s), testnet(s) or relayer(s)",
 	Run: func(cmd *cobra.Command, args []string) {
 
 		// Load chain config
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+	"tm/tm/v2/execute"
+	"tm/tm/v2/ux"
+)
+
+var (
+	flagMaxSize   uint64
+	flagRetention uint
+)
+
+// logWriterCmd is started by tm to write the output of a node or relayer to its log and rotate the log by size.
+var logWriterCmd = &cobra.Command{
+	Use:    "log-writer [home]",
+	Short:  "Write the standard input to the log in the home folder",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := execute.WriteLog(os.Stdin, args[0], execute.LogRotation{
+			MaxSize:   viper.GetUint64("max-size"),
+			Retention: viper.GetUint("retention"),
+		})
+		if err != nil {
+			ux.Fatal("could not write log: %s", err)
+		}
+	},
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/spf13/cobra"
 	"tm/tm/v2/context"
 	"tm/tm/v2/startstop"
+	"tm/tm/v2/ux"
 )
 
 var resetCmd = &cobra.Command{
@@ -16,6 +17,9 @@ var resetCmd = &cobra.Command{
 		ctx := context.New(args)
 
 		// Execute reset
-		startstop.Reset(ctx)
+		results := startstop.Reset(ctx)
+		ux.Summary(results)
+		ux.JSON(results)
+		ux.ExitOnFailure(results)
 	},
 }
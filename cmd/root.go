@@ -4,6 +4,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"os"
+	"time"
+	"tm/tm/v2/consts"
 	"tm/tm/v2/utils"
 	"tm/tm/v2/ux"
 	version "tm/tm/v2/version"
@@ -13,6 +15,12 @@ var rootCmd = &cobra.Command{
 	Use:     "tm",
 	Short:   "Testnets Manager",
 	Version: version.Version,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		output := viper.GetString("output")
+		if output != "text" && output != "json" {
+			ux.Fatal("invalid output format %s, use text or json", output)
+		}
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		_ = cmd.Help()
 	},
@@ -23,6 +31,7 @@ var (
 	flagConfig string
 	flagDebug  bool
 	flagQuiet  bool
+	flagOutput string
 )
 
 func init() {
@@ -40,6 +49,13 @@ func init() {
 		ux.Fatal("could not bind quiet flag")
 	}
 
+	// --output -o
+	rootCmd.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format (text|json)")
+	err = viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	if err != nil {
+		ux.Fatal("could not bind output flag")
+	}
+
 	// --home
 	homeDir, _ := os.UserHomeDir()
 	rootCmd.PersistentFlags().StringVarP(&flagHome, "home", "", utils.GetSlashPath("%s/.tm", homeDir), "home directory")
@@ -69,15 +85,141 @@ func init() {
 		ux.Fatal("could not bind follow-and-retry flag")
 	}
 
+	// --sort-by-time for log
+	logCmd.Flags().BoolVarP(&flagSortByTime, "sort-by-time", "", false, "order the lines of all logs by their timestamp instead of their arrival")
+	err = viper.BindPFlag("sort-by-time", logCmd.Flags().Lookup("sort-by-time"))
+	if err != nil {
+		ux.Fatal("could not bind sort-by-time flag")
+	}
+
+	// --level for log
+	logCmd.Flags().StringVarP(&flagLevel, "level", "", "", "show lines of this level and above (trace|debug|info|warn|error)")
+	err = viper.BindPFlag("level", logCmd.Flags().Lookup("level"))
+	if err != nil {
+		ux.Fatal("could not bind level flag")
+	}
+
+	// --module for log
+	logCmd.Flags().StringVarP(&flagModule, "module", "", "", "show lines of these comma-separated modules")
+	err = viper.BindPFlag("module", logCmd.Flags().Lookup("module"))
+	if err != nil {
+		ux.Fatal("could not bind module flag")
+	}
+
+	// --grep for log
+	logCmd.Flags().StringVarP(&flagGrep, "grep", "", "", "show lines whose message or fields match the regular expression")
+	err = viper.BindPFlag("grep", logCmd.Flags().Lookup("grep"))
+	if err != nil {
+		ux.Fatal("could not bind grep flag")
+	}
+
+	// --since for log
+	logCmd.Flags().StringVarP(&flagSince, "since", "", "", "show lines since a time (e.g. 2006-01-02 15:04:05) or a duration ago (e.g. 10m)")
+	err = viper.BindPFlag("since", logCmd.Flags().Lookup("since"))
+	if err != nil {
+		ux.Fatal("could not bind since flag")
+	}
+
+	// --run for log
+	logCmd.Flags().IntVarP(&flagRun, "run", "", 0, "show an archived log, -1 is the newest archive")
+	err = viper.BindPFlag("run", logCmd.Flags().Lookup("run"))
+	if err != nil {
+		ux.Fatal("could not bind run flag")
+	}
+
+	// --max-size and --retention for log-writer
+	logWriterCmd.Flags().Uint64VarP(&flagMaxSize, "max-size", "", 0, "log size in bytes at which the log is archived")
+	err = viper.BindPFlag("max-size", logWriterCmd.Flags().Lookup("max-size"))
+	if err != nil {
+		ux.Fatal("could not bind max-size flag")
+	}
+	logWriterCmd.Flags().UintVarP(&flagRetention, "retention", "", 0, "number of archived logs kept")
+	err = viper.BindPFlag("retention", logWriterCmd.Flags().Lookup("retention"))
+	if err != nil {
+		ux.Fatal("could not bind retention flag")
+	}
+
+	// --wait for health
+	healthCmd.Flags().BoolVarP(&flagWait, "wait", "", false, "wait until all checks pass or the timeout expires")
+	err = viper.BindPFlag("wait", healthCmd.Flags().Lookup("wait"))
+	if err != nil {
+		ux.Fatal("could not bind wait flag")
+	}
+
+	// --timeout for health
+	healthCmd.Flags().DurationVarP(&flagHealthTimeout, "timeout", "", consts.HealthTimeout, "time to wait for the checks to pass with --wait")
+	err = viper.BindPFlag("health-timeout", healthCmd.Flags().Lookup("timeout"))
+	if err != nil {
+		ux.Fatal("could not bind timeout flag")
+	}
+
+	// --block-window for health
+	healthCmd.Flags().DurationVarP(&flagBlockWindow, "block-window", "", consts.HealthBlockWindow, "time within which a node has to produce a block")
+	err = viper.BindPFlag("block-window", healthCmd.Flags().Lookup("block-window"))
+	if err != nil {
+		ux.Fatal("could not bind block-window flag")
+	}
+
+	// --relayer-window for health
+	healthCmd.Flags().DurationVarP(&flagRelayerWindow, "relayer-window", "", consts.HealthRelayerWindow, "time within which a relayer has to write to its log, 0 disables the check, overrides relayer_window in the config")
+	err = viper.BindPFlag("relayer-window", healthCmd.Flags().Lookup("relayer-window"))
+	if err != nil {
+		ux.Fatal("could not bind relayer-window flag")
+	}
+
+	// --supervise for start
+	startCmd.Flags().BoolVarP(&flagSupervise, "supervise", "", false, "stay in the foreground and restart nodes that crash")
+	err = viper.BindPFlag("supervise", startCmd.Flags().Lookup("supervise"))
+	if err != nil {
+		ux.Fatal("could not bind supervise flag")
+	}
+
+	// --port for ibc
+	ibcCmd.PersistentFlags().StringVarP(&flagPort, "port", "", consts.DefaultIBCPort, "port on both chains")
+	err = viper.BindPFlag("port", ibcCmd.PersistentFlags().Lookup("port"))
+	if err != nil {
+		ux.Fatal("could not bind port flag")
+	}
+
+	// --channel-version for ibc connect
+	ibcConnectCmd.Flags().StringVarP(&flagChannelVersion, "channel-version", "", consts.DefaultIBCVersion, "channel version")
+	err = viper.BindPFlag("channel-version", ibcConnectCmd.Flags().Lookup("channel-version"))
+	if err != nil {
+		ux.Fatal("could not bind channel-version flag")
+	}
+
+	// --receiver for ibc transfer
+	ibcTransferCmd.Flags().StringVarP(&flagReceiver, "receiver", "", "", "receiver wallet on the destination chain (default: same as sender)")
+	err = viper.BindPFlag("receiver", ibcTransferCmd.Flags().Lookup("receiver"))
+	if err != nil {
+		ux.Fatal("could not bind receiver flag")
+	}
+
+	// --timeout for ibc transfer
+	ibcTransferCmd.Flags().DurationVarP(&flagTransferTimeout, "timeout", "", 2*time.Minute, "time to wait for the packet to be received and acknowledged")
+	err = viper.BindPFlag("transfer-timeout", ibcTransferCmd.Flags().Lookup("timeout"))
+	if err != nil {
+		ux.Fatal("could not bind timeout flag")
+	}
+
 	// sub-commands
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(configureCmd)
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(resetCmd)
 	rootCmd.AddCommand(logCmd)
 	rootCmd.AddCommand(keysCmd)
+	rootCmd.AddCommand(superviseCmd)
+	rootCmd.AddCommand(topCmd)
+	rootCmd.AddCommand(healthCmd)
+	rootCmd.AddCommand(diagnoseCmd)
+	rootCmd.AddCommand(logWriterCmd)
+	rootCmd.AddCommand(ibcCmd)
+	ibcCmd.AddCommand(ibcConnectCmd)
+	ibcCmd.AddCommand(ibcTransferCmd)
 }
 
 func Execute() error {
@@ -2,20 +2,32 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"tm/tm/v2/context"
 	"tm/tm/v2/startstop"
+	"tm/tm/v2/ux"
 )
 
+var flagSupervise bool
+
 var startCmd = &cobra.Command{
 	Use:     "start",
 	Aliases: []string{"run"},
-	Short:   "Start one or more node(s) or testnet(s)",
+	Short:   "Start one or more node(s), testnet(s) or relayer(s)",
 	Run: func(cmd *cobra.Command, args []string) {
 
 		// Load chain config
 		ctx := context.New(args)
 
 		// Execute start
-		startstop.Start(ctx)
+		results := startstop.Start(ctx)
+		ux.Summary(results)
+		ux.JSON(results)
+
+		// Execute supervise
+		if viper.GetBool("supervise") {
+			startstop.Supervise(ctx)
+		}
+		ux.ExitOnFailure(results)
 	},
 }
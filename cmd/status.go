@@ -9,7 +9,7 @@ import (
 var statusCmd = &cobra.Command{
 	Use:     "status",
 	Aliases: []string{"stat"},
-	Short:   "Get the status of one or more node(s) or testnet(s)",
+	Short:   "Get the status of one or more node(s), testnet(s) or relayer(s)",
 	Run: func(cmd *cobra.Command, args []string) {
 
 		// Load chain config
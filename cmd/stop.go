@@ -4,18 +4,22 @@ import (
 	"github.com/spf13/cobra"
 	"tm/tm/v2/context"
 	"tm/tm/v2/startstop"
+	"tm/tm/v2/ux"
 )
 
 var stopCmd = &cobra.Command{
 	Use:     "stop",
 	Aliases: []string{"stop"},
-	Short:   "Stop one or more node(s) or testnet(s)",
+	Short:   "Stop one or more node(s), testnet(s) or relayer(s)",
 	Run: func(cmd *cobra.Command, args []string) {
 
 		// Load chain config
 		ctx := context.New(args)
 
 		// Execute stop
-		startstop.Stop(ctx)
+		results := startstop.Stop(ctx)
+		ux.Summary(results)
+		ux.JSON(results)
+		ux.ExitOnFailure(results)
 	},
 }
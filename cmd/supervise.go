@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"tm/tm/v2/context"
+	"tm/tm/v2/startstop"
+	"tm/tm/v2/ux"
+)
+
+var superviseCmd = &cobra.Command{
+	Use:   "supervise",
+	Short: "Start one or more node(s) or testnet(s) and restart them when they crash",
+	Run: func(cmd *cobra.Command, args []string) {
+
+		// Load chain config
+		ctx := context.New(args)
+
+		// Execute start and supervise
+		results := startstop.Start(ctx)
+		ux.Summary(results)
+		ux.JSON(results)
+		startstop.Supervise(ctx)
+		ux.ExitOnFailure(results)
+	},
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"tm/tm/v2/context"
+	"tm/tm/v2/startstop"
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Show a live dashboard of one or more node(s), testnet(s) or relayer(s)",
+	Run: func(cmd *cobra.Command, args []string) {
+
+		// Load chain config
+		ctx := context.New(args)
+
+		// Execute top
+		startstop.Top(ctx)
+	},
+}
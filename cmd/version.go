@@ -11,5 +11,6 @@ var versionCmd = &cobra.Command{
 	Short: "Print version number",
 	Run: func(cmd *cobra.Command, args []string) {
 		ux.Info(version.Version)
+		ux.JSON(map[string]string{"version": version.Version})
 	},
 }
@@ -10,6 +10,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 	"tm/tm/v2/consts"
 	"tm/tm/v2/tmconfig"
 	"tm/tm/v2/utils"
@@ -25,7 +26,18 @@ type Config struct {
 	Wallets          []Wallet                `toml:"wallet,omitempty"`
 	Chains           map[string]*ChainConfig `toml:"-"`
 	Hermes           []HermesConfig          `toml:"hermes,omitempty"`
+	Relayers         []RelayerConfig         `toml:"relayer,omitempty"`
+	Channels         []ChannelConfig         `toml:"channel,omitempty"`
 	Port             uint                    `toml:"port,omitzero"`
+	StartupTimeout   uint                    `toml:"startup_timeout,omitzero"`
+	StopTimeout      uint                    `toml:"stop_timeout,omitzero"`
+	MaxRestarts      uint                    `toml:"max_restarts,omitzero"`
+	LogMaxSize       uint                    `toml:"log_max_size,omitzero"`
+	LogRetention     uint                    `toml:"log_retention,omitzero"`
+	RelayerWindow    *uint                   `toml:"relayer_window,omitempty"` // seconds, zero disables the relayer activity check
+	Prometheus       bool                    `toml:"prometheus,omitempty"`
+	ConfigToml       map[string]interface{}  `toml:"config_toml,omitempty"`
+	AppToml          map[string]interface{}  `toml:"app_toml,omitempty"`
 	Filename         *tmconfig.Filename      `toml:"-"`
 }
 
@@ -41,29 +53,59 @@ type HermesConfig struct {
 	Nodes            []string `toml:"nodes,omitempty"`
 }
 
+// RelayerConfig defines the Go relayer (rly) entries in the configuration file.
+type RelayerConfig struct {
+	Binary    string   `toml:"binary,omitempty"`
+	Home      string   `toml:"home,omitempty"`
+	Mnemonics string   `toml:"mnemonics,omitempty"`
+	Nodes     []string `toml:"nodes,omitempty"`
+}
+
+// ChannelConfig defines an IBC channel between two chains that is set up automatically.
+type ChannelConfig struct {
+	Chains  []string `toml:"chains"`
+	Port    string   `toml:"port,omitempty"`
+	Version string   `toml:"version,omitempty"`
+}
+
 // ChainConfig defines the Testnets Manager chain configuration format
 type ChainConfig struct {
-	HDPath       string           `toml:"hdpath,omitempty"`
-	Binary       string           `toml:"binary,omitempty"`
-	Home         string           `toml:"home,omitempty"`
-	StopMaintain bool             `toml:"stop_maintain,omitempty"`
-	denom        string           `toml:"denom,omitempty"`
-	Nodes        map[string]*Node `toml:"-"`
+	HDPath       string                 `toml:"hdpath,omitempty"`
+	Binary       string                 `toml:"binary,omitempty"`
+	Home         string                 `toml:"home,omitempty"`
+	StopMaintain bool                   `toml:"stop_maintain,omitempty"`
+	Denom        string                 `toml:"denom,omitempty"`         // default is the bond denomination of the generated genesis
+	DisplayDenom string                 `toml:"display_denom,omitempty"` // registered in the genesis denomination metadata
+	Decimals     uint                   `toml:"decimals,omitzero"`       // exponent of the display denomination
+	Balance      string                 `toml:"balance,omitempty"`       // default genesis balance of validators, relayers and wallets
+	Stake        string                 `toml:"stake,omitempty"`         // default self-delegation of validators
+	ConfigToml   map[string]interface{} `toml:"config_toml,omitempty"`
+	AppToml      map[string]interface{} `toml:"app_toml,omitempty"`
+	Genesis      map[string]interface{} `toml:"genesis,omitempty"`
+	Nodes        map[string]*Node       `toml:"-"`
 }
 
 type Node struct {
-	Binary       string   `toml:"binary,omitempty"`
-	Home         string   `toml:"home,omitempty"`
-	Mnemonics    string   `toml:"mnemonics,omitempty"` // Not used on full nodes
-	Port         uint     `toml:"port,omitzero"`
-	Validator    bool     `toml:"validator,omitempty"`
-	StopMaintain bool     `toml:"stop_maintain,omitempty"`
-	Connections  []string `toml:"connections,omitempty"` // default is to connect all validators to each other and all full nodes to all validators
+	Binary       string                 `toml:"binary,omitempty"`
+	Home         string                 `toml:"home,omitempty"`
+	Mnemonics    string                 `toml:"mnemonics,omitempty"` // Not used on full nodes
+	Port         uint                   `toml:"port,omitzero"`
+	Validator    bool                   `toml:"validator,omitempty"`
+	StopMaintain bool                   `toml:"stop_maintain,omitempty"`
+	Connections  []string               `toml:"connections,omitempty"` // default is to connect all validators to each other and all full nodes to all validators
+	Balance      string                 `toml:"balance,omitempty"`     // Not used on full nodes
+	Stake        string                 `toml:"stake,omitempty"`       // Not used on full nodes
+	ConfigToml   map[string]interface{} `toml:"config_toml,omitempty"`
+	AppToml      map[string]interface{} `toml:"app_toml,omitempty"`
 }
 
+// ReservedNames are the tables of a chain or node that are not nodes.
+var ReservedNames = []string{"config_toml", "app_toml", "genesis"}
+
 type Wallet struct {
 	Name      string `toml:"name"`
 	Mnemonics string `toml:"mnemonics,omitempty"`
+	Balance   string `toml:"balance,omitempty"`
 }
 
 func New() Config {
@@ -261,6 +303,138 @@ func (cfg Config) GetStopMaintain(nodeFullName string) bool {
 	return result
 }
 
+// GetBalance returns the genesis balance of a validator. Node balance overrides the chain balance.
+func (cfg Config) GetBalance(nodeFullName string) string {
+	chain, node := cfg.FindNode(nodeFullName)
+	result := node.Balance
+	if result == "" {
+		result = chain.Balance
+	}
+	if result == "" {
+		result = consts.GenesisBalance
+	}
+	return cfg.getCoins(nodeFullName, result)
+}
+
+// GetChainBalance returns the default genesis balance of the accounts on a chain, e.g. relayer accounts.
+func (cfg Config) GetChainBalance(chainName string) string {
+	result := cfg.Chains[chainName].Balance
+	if result == "" {
+		result = consts.GenesisBalance
+	}
+	return cfg.getCoins(chainName, result)
+}
+
+// GetWalletBalance returns the genesis balance of a wallet on a chain. Wallet balance overrides the chain balance.
+func (cfg Config) GetWalletBalance(chainName string, walletName string) string {
+	for _, wallet := range cfg.Wallets {
+		if wallet.Name == walletName && wallet.Balance != "" {
+			return cfg.getCoins(chainName, wallet.Balance)
+		}
+	}
+	return cfg.GetChainBalance(chainName)
+}
+
+// GetStake returns the self-delegation of a validator. Node stake overrides the chain stake.
+func (cfg Config) GetStake(nodeFullName string) string {
+	chain, node := cfg.FindNode(nodeFullName)
+	result := node.Stake
+	if result == "" {
+		result = chain.Stake
+	}
+	if result == "" {
+		result = consts.GenesisStake
+	}
+	return cfg.getCoins(nodeFullName, result)
+}
+
+// getCoins adds the chain denomination to the amounts without a denomination in a comma-separated list of coins.
+func (cfg Config) getCoins(fullNodename string, coins string) string {
+	var result []string
+	for _, coin := range strings.Split(coins, ",") {
+		coin = strings.TrimSpace(coin)
+		if match := coinPattern.FindStringSubmatch(coin); match != nil && match[2] == "" {
+			coin += cfg.GetDenom(fullNodename)
+		}
+		result = append(result, coin)
+	}
+	return strings.Join(result, ",")
+}
+
+// GetConfigTomlOverrides returns the config.toml entries of a node. Node entries override chain entries and chain entries
+// override global entries. Keys are dotted paths, e.g. consensus.timeout_commit.
+func (cfg Config) GetConfigTomlOverrides(nodeFullName string) map[string]interface{} {
+	chain, node := cfg.FindNode(nodeFullName)
+	return mergeOverrides(cfg.ConfigToml, chain.ConfigToml, node.ConfigToml)
+}
+
+// GetAppTomlOverrides returns the app.toml entries of a node. Node entries override chain entries and chain entries
+// override global entries. Keys are dotted paths, e.g. pruning.
+func (cfg Config) GetAppTomlOverrides(nodeFullName string) map[string]interface{} {
+	chain, node := cfg.FindNode(nodeFullName)
+	return mergeOverrides(cfg.AppToml, chain.AppToml, node.AppToml)
+}
+
+// mergeOverrides merges the override levels, later levels win.
+func mergeOverrides(levels ...map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, level := range levels {
+		for key, value := range level {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// GetStartupTimeout returns the time to wait for a node to produce blocks after it was started.
+func (cfg Config) GetStartupTimeout() time.Duration {
+	if cfg.StartupTimeout == 0 {
+		return consts.StartupTimeout * time.Second
+	}
+	return time.Duration(cfg.StartupTimeout) * time.Second
+}
+
+// GetStopTimeout returns the time to wait for a process to exit after each stop signal.
+func (cfg Config) GetStopTimeout() time.Duration {
+	if cfg.StopTimeout == 0 {
+		return consts.StopTimeout * time.Second
+	}
+	return time.Duration(cfg.StopTimeout) * time.Second
+}
+
+// GetMaxRestarts returns the number of times a supervisor restarts a crashed node.
+func (cfg Config) GetMaxRestarts() uint {
+	if cfg.MaxRestarts == 0 {
+		return consts.MaxRestarts
+	}
+	return cfg.MaxRestarts
+}
+
+// GetLogMaxSize returns the size in bytes at which a running process log is archived and a new log is started.
+func (cfg Config) GetLogMaxSize() uint64 {
+	if cfg.LogMaxSize == 0 {
+		return consts.LogMaxSize * 1024 * 1024
+	}
+	return uint64(cfg.LogMaxSize) * 1024 * 1024
+}
+
+// GetLogRetention returns the number of archived logs kept in a home folder.
+func (cfg Config) GetLogRetention() uint {
+	if cfg.LogRetention == 0 {
+		return consts.LogRetention
+	}
+	return cfg.LogRetention
+}
+
+// GetRelayerWindow returns the time within which a healthy relayer instance has written to its log. Zero disables the
+// check, because some relayers do not log while there are no packets to relay.
+func (cfg Config) GetRelayerWindow() time.Duration {
+	if cfg.RelayerWindow == nil {
+		return consts.HealthRelayerWindow
+	}
+	return time.Duration(*cfg.RelayerWindow) * time.Second
+}
+
 func (cfg Config) GetPort(nodeFullName string) uint {
 	_, node := cfg.FindNode(nodeFullName)
 	return node.Port
@@ -294,6 +468,25 @@ func (cfg Config) GetGRPCWEBPort(nodeFullName string) uint {
 	return cfg.GetPort(nodeFullName) + 6
 }
 
+func (cfg Config) GetPrometheusPort(nodeFullName string) uint {
+	return cfg.GetPort(nodeFullName) + 7
+}
+
+// GetPrometheusConfig returns the path of the Prometheus scrape configuration of all nodes and relayer instances.
+func (cfg Config) GetPrometheusConfig() string {
+	result := ""
+	if cfg.Home != "" {
+		result = utils.GetSlashPath("%s/prometheus.yml", cfg.Home)
+	} else {
+		result = utils.GetSlashPath("%s/prometheus.yml", tmconfig.FindConfigFilename().Dir)
+	}
+	expanded, err := shell.Expand(result, nil)
+	if err != nil {
+		ux.Fatal(err.Error())
+	}
+	return expanded
+}
+
 func (cfg Config) GetPath(fullNodename string, suffix string) string {
 	return utils.GetSlashPath("%s/%s", cfg.GetHome(fullNodename), suffix)
 }
@@ -305,8 +498,8 @@ func (cfg Config) GetChainPath(fullNodename string, suffix string) string {
 func (cfg Config) GetDenom(fullNodename string) string {
 	chainName := strings.Split(fullNodename, ".")[0]
 	chain := cfg.Chains[chainName]
-	if chain.denom != "" {
-		return chain.denom
+	if chain.Denom != "" {
+		return chain.Denom
 	}
 	chainGenesis := cfg.GetChainPath(fullNodename, "config/genesis.json")
 	if denom, ok := utils.GetConfigEntry(chainGenesis, "app_state.staking.params.bond_denom").(string); !ok {
@@ -342,6 +535,8 @@ func (cfg Config) GetConnections(fullNodename string) []string {
 	return result
 }
 
+// GetMnemonics returns the mnemonics of a wallet stored in the chain home. Empty string is returned if the wallet does
+// not exist.
 func (cfg Config) GetMnemonics(chainName string, walletName string) string {
 	home := cfg.GetChainHome(chainName)
 	file, err := ioutil.ReadFile(consts.GetMnemonics(home, walletName))
@@ -360,3 +555,263 @@ func (cfg Config) GetMnemonics(chainName string, walletName string) string {
 	}
 	return data.Mnemonic
 }
+
+// GetAddress returns the address of a wallet stored in the chain home. Empty string is returned if the wallet does not
+// exist.
+func (cfg Config) GetAddress(chainName string, walletName string) string {
+	home := cfg.GetChainHome(chainName)
+	file, err := ioutil.ReadFile(consts.GetMnemonics(home, walletName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ""
+		}
+		ux.Fatal("could not read address %s: %s", walletName, err)
+	}
+
+	var data utils.Keys
+	err = json.Unmarshal(file, &data)
+	if err != nil {
+		ux.Warn("could not unmarshal keys output from %s: %s", chainName, err)
+		ux.Warn("Output string: %s", string(file))
+	}
+	return data.Address
+}
+
+// GetHermesName returns the name of the Hermes instance defined at index i. The name is also used as the key name of
+// the Hermes wallet on each chain.
+func GetHermesName(i int) string {
+	return fmt.Sprintf("hermes%d", i)
+}
+
+// FindHermes returns the index and the configuration of a Hermes instance by name (e.g. "hermes0").
+func (cfg Config) FindHermes(hermesName string) (int, *HermesConfig) {
+	for i := range cfg.Hermes {
+		if GetHermesName(i) == hermesName {
+			return i, &cfg.Hermes[i]
+		}
+	}
+	ux.Fatal("hermes %s not found in config", hermesName)
+	return 0, nil
+}
+
+// GetHermesHome returns the folder of the Hermes instance. It is the folder of the Hermes config file.
+func (cfg Config) GetHermesHome(hermesName string) string {
+	return filepath.Dir(cfg.GetHermesConfig(hermesName))
+}
+
+// GetHermesBinary returns the Hermes binary of the instance. It defaults to "hermes" in the PATH.
+func (cfg Config) GetHermesBinary(hermesName string) string {
+	_, hermes := cfg.FindHermes(hermesName)
+	if hermes.Binary == "" {
+		return utils.FindOSBinary("hermes")
+	}
+	result, err := shell.Expand(hermes.Binary, nil)
+	if err != nil {
+		ux.Fatal("binary not found, %s", err.Error())
+	}
+	return result
+}
+
+// GetHermesConfig returns the path to the Hermes configuration file. If it is not set, it defaults to
+// "<home>/<hermesName>/config.toml".
+func (cfg Config) GetHermesConfig(hermesName string) string {
+	_, hermes := cfg.FindHermes(hermesName)
+	result := hermes.Config
+	if result == "" {
+		if cfg.Home != "" {
+			result = utils.GetSlashPath("%s/%s/config.toml", cfg.Home, hermesName)
+		} else {
+			result = utils.GetSlashPath("%s/%s/config.toml", tmconfig.FindConfigFilename().Dir, hermesName)
+		}
+	}
+	expanded, err := shell.Expand(result, nil)
+	if err != nil {
+		ux.Fatal(err.Error())
+	}
+	return expanded
+}
+
+func (cfg Config) GetHermesLogLevel(hermesName string) string {
+	_, hermes := cfg.FindHermes(hermesName)
+	if hermes.LogLevel == "" {
+		return "info"
+	}
+	return hermes.LogLevel
+}
+
+func (cfg Config) GetHermesTelemetryHost(hermesName string) string {
+	_, hermes := cfg.FindHermes(hermesName)
+	if hermes.TelemetryHost == "" {
+		return "127.0.0.1"
+	}
+	return hermes.TelemetryHost
+}
+
+// GetHermesTelemetryEnabled returns true if the Hermes telemetry is enabled in the Hermes config or Prometheus metrics
+// are enabled globally.
+func (cfg Config) GetHermesTelemetryEnabled(hermesName string) bool {
+	_, hermes := cfg.FindHermes(hermesName)
+	return hermes.TelemetryEnabled || cfg.Prometheus
+}
+
+func (cfg Config) GetHermesTelemetryPort(hermesName string) uint {
+	i, hermes := cfg.FindHermes(hermesName)
+	if hermes.TelemetryPort == 0 {
+		return consts.HermesTelemetryPort + uint(i)
+	}
+	return hermes.TelemetryPort
+}
+
+// FindHermesForChains returns the name of the first Hermes instance that connects both chains. Empty string is returned
+// if no such instance exists.
+func (cfg Config) FindHermesForChains(chainA string, chainB string) string {
+	for i, hermes := range cfg.Hermes {
+		foundA := false
+		foundB := false
+		for _, fullNodename := range hermes.Nodes {
+			chainName := strings.Split(fullNodename, ".")[0]
+			foundA = foundA || chainName == chainA
+			foundB = foundB || chainName == chainB
+		}
+		if foundA && foundB {
+			return GetHermesName(i)
+		}
+	}
+	return ""
+}
+
+// GetHermesNode returns the node that the Hermes instance uses to connect to the chain. Empty string is returned if the
+// instance does not connect to the chain.
+func (cfg Config) GetHermesNode(hermesName string, chainName string) string {
+	_, hermes := cfg.FindHermes(hermesName)
+	for _, fullNodename := range hermes.Nodes {
+		if strings.Split(fullNodename, ".")[0] == chainName {
+			return fullNodename
+		}
+	}
+	return ""
+}
+
+// GetChannel returns the IBC connection details stored in the chain home for a counterparty chain and port. Nil is
+// returned if the channel was not created yet.
+func (cfg Config) GetChannel(chainName string, counterpartyChainName string, port string) *utils.Channel {
+	home := cfg.GetChainHome(chainName)
+	file, err := ioutil.ReadFile(consts.GetChannel(home, port, counterpartyChainName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		ux.Fatal("could not read channel %s on %s: %s", port, chainName, err)
+	}
+
+	var data utils.Channel
+	err = json.Unmarshal(file, &data)
+	if err != nil {
+		ux.Fatal("could not unmarshal channel %s on %s: %s", port, chainName, err)
+	}
+	return &data
+}
+
+// GetRelayerName returns the name of the Go relayer instance defined at index i. The name is also used as the key name
+// of the relayer wallet on each chain.
+func GetRelayerName(i int) string {
+	return fmt.Sprintf("rly%d", i)
+}
+
+// FindRelayer returns the index and the configuration of a Go relayer instance by name (e.g. "rly0").
+func (cfg Config) FindRelayer(relayerName string) (int, *RelayerConfig) {
+	for i := range cfg.Relayers {
+		if GetRelayerName(i) == relayerName {
+			return i, &cfg.Relayers[i]
+		}
+	}
+	ux.Fatal("relayer %s not found in config", relayerName)
+	return 0, nil
+}
+
+// GetRelayerHome returns the home folder of the Go relayer instance. If it is not set, it defaults to
+// "<home>/<relayerName>".
+func (cfg Config) GetRelayerHome(relayerName string) string {
+	_, relayer := cfg.FindRelayer(relayerName)
+	result := relayer.Home
+	if result == "" {
+		if cfg.Home != "" {
+			result = utils.GetSlashPath("%s/%s", cfg.Home, relayerName)
+		} else {
+			result = utils.GetSlashPath("%s/%s", tmconfig.FindConfigFilename().Dir, relayerName)
+		}
+	}
+	expanded, err := shell.Expand(result, nil)
+	if err != nil {
+		ux.Fatal(err.Error())
+	}
+	return expanded
+}
+
+// GetRelayerBinary returns the Go relayer binary of the instance. It defaults to "rly" in the PATH.
+func (cfg Config) GetRelayerBinary(relayerName string) string {
+	_, relayer := cfg.FindRelayer(relayerName)
+	if relayer.Binary == "" {
+		return utils.FindOSBinary("rly")
+	}
+	result, err := shell.Expand(relayer.Binary, nil)
+	if err != nil {
+		ux.Fatal("binary not found, %s", err.Error())
+	}
+	return result
+}
+
+// GetRelayerConfig returns the path to the Go relayer configuration file in the relayer home.
+func (cfg Config) GetRelayerConfig(relayerName string) string {
+	return utils.GetSlashPath("%s/config/config.yaml", cfg.GetRelayerHome(relayerName))
+}
+
+// GetRelayerDebugPort returns the port of the Go relayer debug server, which serves the metrics.
+func (cfg Config) GetRelayerDebugPort(relayerName string) uint {
+	i, _ := cfg.FindRelayer(relayerName)
+	return consts.RelayerDebugPort + uint(i)
+}
+
+// FindRelayerForChains returns the name of the first Go relayer instance that connects both chains. Empty string is
+// returned if no such instance exists.
+func (cfg Config) FindRelayerForChains(chainA string, chainB string) string {
+	for i := range cfg.Relayers {
+		relayerName := GetRelayerName(i)
+		if cfg.GetRelayerNode(relayerName, chainA) != "" && cfg.GetRelayerNode(relayerName, chainB) != "" {
+			return relayerName
+		}
+	}
+	return ""
+}
+
+// GetRelayerNode returns the node that the Go relayer instance uses to connect to the chain. Empty string is returned
+// if the instance does not connect to the chain.
+func (cfg Config) GetRelayerNode(relayerName string, chainName string) string {
+	_, relayer := cfg.FindRelayer(relayerName)
+	for _, fullNodename := range relayer.Nodes {
+		if strings.Split(fullNodename, ".")[0] == chainName {
+			return fullNodename
+		}
+	}
+	return ""
+}
+
+// GetRelayerPath returns the name of the Go relayer path between two chains (e.g. "testnet-1_testnet-2"). The chain
+// listed first in the instance nodes is the source of the path. The second result is true if chainB is the source.
+func (cfg Config) GetRelayerPath(relayerName string, chainA string, chainB string) (string, bool) {
+	_, relayer := cfg.FindRelayer(relayerName)
+	for _, fullNodename := range relayer.Nodes {
+		switch strings.Split(fullNodename, ".")[0] {
+		case chainA:
+			return GetRelayerPathName(chainA, chainB), false
+		case chainB:
+			return GetRelayerPathName(chainB, chainA), true
+		}
+	}
+	return "", false
+}
+
+// GetRelayerPathName returns the name of the Go relayer path from the source to the destination chain.
+func GetRelayerPathName(srcChain string, dstChain string) string {
+	return fmt.Sprintf("%s_%s", srcChain, dstChain)
+}
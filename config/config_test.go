@@ -1,6 +1,8 @@
 package config
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 	"tm/tm/v2/tmconfig"
 	"tm/tm/v2/utils"
@@ -67,6 +69,11 @@ func newDebugConfig() Config {
 			TelemetryPort:    3002,
 			Nodes:            []string{"testnet-1.validator1", "testnet-2.fullnode1"},
 		}},
+		Relayers: []RelayerConfig{{
+			Binary: utils.FindOSBinary("rly"),
+			Home:   "$HOME/.relayer",
+			Nodes:  []string{"testnet-1.validator1", "testnet-2.validator1"},
+		}},
 		Port:     26600,
 		Filename: &tmconfig.Filename{},
 	}
@@ -97,3 +104,212 @@ func TestConfig(t *testing.T) {
 	}
 	ux.Info(string(bytes))
 }
+
+func newOverridesConfig() Config {
+	return Config{
+		Chains: map[string]*ChainConfig{"testnet-1": {
+			ConfigToml: map[string]interface{}{
+				"consensus.timeout_commit": "2s",
+				"mempool.size":             int64(1000),
+			},
+			Nodes: map[string]*Node{"validator1": {
+				Validator:  true,
+				ConfigToml: map[string]interface{}{"consensus.timeout_commit": "1s"},
+				AppToml:    map[string]interface{}{"pruning": "nothing"},
+			}, "fullnode1": {}},
+		}, "testnet-2": {
+			Nodes: map[string]*Node{"validator1": {
+				Validator: true,
+			}},
+		}},
+		ConfigToml: map[string]interface{}{"log_format": "json"},
+		AppToml:    map[string]interface{}{"pruning": "everything"},
+		Filename:   &tmconfig.Filename{},
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	cfg := roundTrip(t, newOverridesConfig())
+	for _, test := range []struct {
+		name      string
+		overrides map[string]interface{}
+		expected  map[string]interface{}
+	}{
+		{"node config.toml", cfg.GetConfigTomlOverrides("testnet-1.validator1"), map[string]interface{}{"consensus.timeout_commit": "1s", "mempool.size": int64(1000), "log_format": "json"}},
+		{"chain config.toml", cfg.GetConfigTomlOverrides("testnet-1.fullnode1"), map[string]interface{}{"consensus.timeout_commit": "2s", "mempool.size": int64(1000), "log_format": "json"}},
+		{"global config.toml", cfg.GetConfigTomlOverrides("testnet-2.validator1"), map[string]interface{}{"log_format": "json"}},
+		{"node app.toml", cfg.GetAppTomlOverrides("testnet-1.validator1"), map[string]interface{}{"pruning": "nothing"}},
+		{"global app.toml", cfg.GetAppTomlOverrides("testnet-2.validator1"), map[string]interface{}{"pruning": "everything"}},
+	} {
+		if !reflect.DeepEqual(test.overrides, test.expected) {
+			t.Errorf("unexpected %s overrides %v, expected %v", test.name, test.overrides, test.expected)
+		}
+	}
+	for _, name := range []string{"config_toml", "app_toml"} {
+		if _, ok := cfg.Chains["testnet-1"].Nodes[name]; ok {
+			t.Errorf("%s table decoded as a node", name)
+		}
+	}
+}
+
+// roundTrip encodes and decodes the config.
+func roundTrip(t *testing.T, cfg Config) Config {
+	t.Helper()
+	bytes, err := cfg.CustomMarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result Config
+	err = result.CustomUnmarshal(bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result.Filename = cfg.Filename
+	return result
+}
+
+func newGenesisConfig() Config {
+	return Config{
+		Chains: map[string]*ChainConfig{"testnet-1": {
+			Genesis: map[string]interface{}{
+				"app_state.gov.voting_params.voting_period": "20s",
+				"app_state.staking.params.max_validators":   int64(10),
+			},
+			Nodes: map[string]*Node{"validator1": {
+				Validator: true,
+			}},
+		}},
+		Filename: &tmconfig.Filename{},
+	}
+}
+
+func TestGenesisOverrides(t *testing.T) {
+	cfg := roundTrip(t, newGenesisConfig())
+	genesis := cfg.Chains["testnet-1"].Genesis
+	if len(genesis) != 2 || genesis["app_state.gov.voting_params.voting_period"] != "20s" || genesis["app_state.staking.params.max_validators"] != int64(10) {
+		t.Errorf("unexpected genesis overrides %v", genesis)
+	}
+	if _, ok := cfg.Chains["testnet-1"].Nodes["genesis"]; ok {
+		t.Errorf("genesis table decoded as a node")
+	}
+}
+
+func newBalancesConfig() Config {
+	return Config{
+		Wallets: []Wallet{{
+			Name: "wallet1",
+		}, {
+			Name:    "wallet2",
+			Balance: "0",
+		}},
+		Chains: map[string]*ChainConfig{"testnet-1": {
+			Denom:   "uatom",
+			Balance: "100000",
+			Stake:   "1000",
+			Nodes: map[string]*Node{"validator1": {
+				Validator: true,
+				Balance:   "5000,10ibc/ABC",
+				Stake:     "3000",
+			}, "validator2": {
+				Validator: true,
+			}},
+		}},
+		Filename: &tmconfig.Filename{},
+	}
+}
+
+func TestBalances(t *testing.T) {
+	cfg := roundTrip(t, newBalancesConfig())
+	for _, test := range []struct {
+		name     string
+		result   string
+		expected string
+	}{
+		{"validator balance", cfg.GetBalance("testnet-1.validator1"), "5000uatom,10ibc/ABC"},
+		{"validator stake", cfg.GetStake("testnet-1.validator1"), "3000uatom"},
+		{"chain balance", cfg.GetChainBalance("testnet-1"), "100000uatom"},
+		{"chain stake", cfg.GetStake("testnet-1.validator2"), "1000uatom"},
+		{"wallet balance", cfg.GetWalletBalance("testnet-1", "wallet2"), "0uatom"},
+		{"default wallet balance", cfg.GetWalletBalance("testnet-1", "wallet1"), "100000uatom"},
+	} {
+		if test.result != test.expected {
+			t.Errorf("unexpected %s %s, expected %s", test.name, test.result, test.expected)
+		}
+	}
+}
+
+func newDenomConfig() Config {
+	return Config{
+		Chains: map[string]*ChainConfig{"testnet-1": {
+			Denom:        "uatom",
+			DisplayDenom: "atom",
+			Decimals:     6,
+			Nodes: map[string]*Node{"validator1": {
+				Validator: true,
+			}},
+		}, "testnet-2": {
+			Nodes: map[string]*Node{"validator1": {
+				Validator: true,
+			}},
+		}},
+		Filename: &tmconfig.Filename{},
+	}
+}
+
+func TestDenom(t *testing.T) {
+	cfg := newDenomConfig()
+	bytes, err := cfg.CustomMarshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(bytes), "denom =") != 2 || strings.Count(string(bytes), "decimals =") != 1 {
+		t.Errorf("unset denominations encoded:\n%s", bytes)
+	}
+
+	cfg = roundTrip(t, cfg)
+	chain := cfg.Chains["testnet-1"]
+	if chain.Denom != "uatom" || chain.DisplayDenom != "atom" || chain.Decimals != 6 {
+		t.Errorf("unexpected denomination %s, display denomination %s, decimals %d", chain.Denom, chain.DisplayDenom, chain.Decimals)
+	}
+	chain = cfg.Chains["testnet-2"]
+	if chain.Denom != "" || chain.DisplayDenom != "" || chain.Decimals != 0 {
+		t.Errorf("unexpected denomination %s, display denomination %s, decimals %d", chain.Denom, chain.DisplayDenom, chain.Decimals)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"debug":     newDebugConfig(),
+		"overrides": newOverridesConfig(),
+		"genesis":   newGenesisConfig(),
+		"balances":  newBalancesConfig(),
+		"denom":     newDenomConfig(),
+	} {
+		// Ports are assigned when the config is decoded.
+		cfg.setPorts()
+		if cfg2 := roundTrip(t, cfg); !reflect.DeepEqual(cfg, cfg2) {
+			t.Errorf("%s config changed in round trip:\n%#v\n%#v", name, cfg, cfg2)
+		}
+	}
+}
+
+func TestRelayerPath(t *testing.T) {
+	cfg := newDebugConfig()
+	if relayerName := cfg.FindRelayerForChains("testnet-2", "testnet-1"); relayerName != "rly0" {
+		t.Fatalf("relayer %q, expected rly0", relayerName)
+	}
+	if node := cfg.GetRelayerNode("rly0", "testnet-2"); node != "testnet-2.validator1" {
+		t.Errorf("node %q, expected testnet-2.validator1", node)
+	}
+	// The path source is the chain listed first in the relayer nodes.
+	if path, reversed := cfg.GetRelayerPath("rly0", "testnet-1", "testnet-2"); path != "testnet-1_testnet-2" || reversed {
+		t.Errorf("path %q, reversed %t", path, reversed)
+	}
+	if path, reversed := cfg.GetRelayerPath("rly0", "testnet-2", "testnet-1"); path != "testnet-1_testnet-2" || !reversed {
+		t.Errorf("path %q, reversed %t", path, reversed)
+	}
+	cfg.Relayers = nil
+	if relayerName := cfg.FindRelayerForChains("testnet-1", "testnet-2"); relayerName != "" {
+		t.Errorf("relayer %q without relayers", relayerName)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/BurntSushi/toml"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"tm/tm/v2/utils"
 )
 
 func extractBool(v interface{}) (bool, error) {
@@ -70,7 +73,7 @@ func extractUint(v interface{}) (uint, error) {
 
 func extractStringSlice(v interface{}) ([]string, error) {
 	if v == nil {
-		return []string{}, nil
+		return nil, nil
 	}
 	var result []string
 	if rawSlice, ok := v.([]interface{}); ok {
@@ -100,6 +103,68 @@ func extractString(v interface{}) (string, error) {
 	}
 }
 
+// extractOverrides flattens a config.toml, app.toml or genesis override table to dotted keys, e.g. consensus.timeout_commit.
+func extractOverrides(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	table, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("could not extract table from %v", v)
+	}
+	if table == nil {
+		return nil, nil
+	}
+	result := make(map[string]interface{})
+	flattenOverrides(result, "", table)
+	return result, nil
+}
+
+func flattenOverrides(result map[string]interface{}, prefix string, table map[string]interface{}) {
+	for key, value := range table {
+		if subTable, ok := value.(map[string]interface{}); ok {
+			flattenOverrides(result, prefix+key+".", subTable)
+			continue
+		}
+		result[prefix+key] = value
+	}
+}
+
+// chainTable returns the chain settings and its nodes as one value, so the encoder writes the nodes as sub-tables of
+// the chain.
+func chainTable(chain *ChainConfig) interface{} {
+	chainType := reflect.TypeOf(*chain)
+	chainValue := reflect.ValueOf(*chain)
+	var fields []reflect.StructField
+	var values []reflect.Value
+	for i := 0; i < chainType.NumField(); i++ {
+		field := chainType.Field(i)
+		if field.PkgPath != "" || field.Tag.Get("toml") == "-" {
+			continue
+		}
+		fields = append(fields, field)
+		values = append(values, chainValue.Field(i))
+	}
+	var nodeNames []string
+	for nodeName := range chain.Nodes {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	for i, nodeName := range nodeNames {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Node%d", i),
+			Type: reflect.TypeOf(chain.Nodes[nodeName]),
+			Tag:  reflect.StructTag(fmt.Sprintf("toml:%s", strconv.Quote(nodeName))),
+		})
+		values = append(values, reflect.ValueOf(chain.Nodes[nodeName]))
+	}
+	result := reflect.New(reflect.StructOf(fields)).Elem()
+	for i, value := range values {
+		result.Field(i).Set(value)
+	}
+	return result.Interface()
+}
+
 func (cfg Config) CustomMarshal() ([]byte, error) {
 	// Encode config
 	var buf bytes.Buffer
@@ -110,19 +175,14 @@ func (cfg Config) CustomMarshal() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	err = encoder.Encode(cfg.Chains)
+	chains := make(map[string]interface{})
+	for chainName, chain := range cfg.Chains {
+		chains[chainName] = chainTable(chain)
+	}
+	err = encoder.Encode(chains)
 	if err != nil {
 		return nil, err
 	}
-	for chainName, chain := range cfg.Chains {
-		for nodeName, node := range chain.Nodes {
-			_, _ = buf.Write([]byte(fmt.Sprintf("\n[%s.%s]\n", chainName, nodeName)))
-			err = encoder.Encode(node) // This will not indent the values properly. It's a shortcoming of the toml library used.
-			if err != nil {
-				return nil, err
-			}
-		}
-	}
 	return buf.Bytes(), err
 }
 
@@ -133,6 +193,16 @@ func (cfg *Config) CustomUnmarshal(data []byte) error {
 		return err
 	}
 
+	// Overrides are kept with dotted keys
+	cfg.ConfigToml, err = extractOverrides(cfg.ConfigToml)
+	if err != nil {
+		return err
+	}
+	cfg.AppToml, err = extractOverrides(cfg.AppToml)
+	if err != nil {
+		return err
+	}
+
 	// Get the whole data decoded, so we can check for invalid values and unparsed items.
 	decoded := make(map[string]interface{})
 	_, err = toml.Decode(string(data), &decoded)
@@ -162,6 +232,8 @@ func (cfg *Config) CustomUnmarshal(data []byte) error {
 				var binary string
 				var home string
 				var denom string
+				var displayDenom string
+				var decimals uint
 				stopMaintain, err = extractBool(chainItem["stop_maintain"])
 				if err != nil {
 					return err
@@ -182,6 +254,36 @@ func (cfg *Config) CustomUnmarshal(data []byte) error {
 				if err != nil {
 					return err
 				}
+				displayDenom, err = extractString(chainItem["display_denom"])
+				if err != nil {
+					return err
+				}
+				decimals, err = extractUint(chainItem["decimals"])
+				if err != nil {
+					return err
+				}
+				var balance, stake string
+				balance, err = extractString(chainItem["balance"])
+				if err != nil {
+					return err
+				}
+				stake, err = extractString(chainItem["stake"])
+				if err != nil {
+					return err
+				}
+				var configToml, appToml, genesis map[string]interface{}
+				configToml, err = extractOverrides(chainItem["config_toml"])
+				if err != nil {
+					return err
+				}
+				appToml, err = extractOverrides(chainItem["app_toml"])
+				if err != nil {
+					return err
+				}
+				genesis, err = extractOverrides(chainItem["genesis"])
+				if err != nil {
+					return err
+				}
 				emptyNodes := make(map[string]*Node)
 				chains[chainName] = &ChainConfig{
 					HDPath:       hdpath,
@@ -189,12 +291,22 @@ func (cfg *Config) CustomUnmarshal(data []byte) error {
 					Home:         home,
 					StopMaintain: stopMaintain,
 					Nodes:        emptyNodes,
-					denom:        denom,
+					Denom:        denom,
+					DisplayDenom: displayDenom,
+					Decimals:     decimals,
+					Balance:      balance,
+					Stake:        stake,
+					ConfigToml:   configToml,
+					AppToml:      appToml,
+					Genesis:      genesis,
 				}
 			}
 		case 2: // one node
 			chainName := keySplit[0]
 			nodeName := keySplit[1]
+			if utils.Contains(ReservedNames, nodeName) {
+				continue // chain level table, not a node
+			}
 			if _, ok := chains[chainName]; !ok {
 				return fmt.Errorf("%s.%s chain undefined", chainName, nodeName)
 			}
@@ -241,6 +353,24 @@ func (cfg *Config) CustomUnmarshal(data []byte) error {
 					if err != nil {
 						return err
 					}
+					var balance, stake string
+					balance, err = extractString(nodeItem["balance"])
+					if err != nil {
+						return err
+					}
+					stake, err = extractString(nodeItem["stake"])
+					if err != nil {
+						return err
+					}
+					var configToml, appToml map[string]interface{}
+					configToml, err = extractOverrides(nodeItem["config_toml"])
+					if err != nil {
+						return err
+					}
+					appToml, err = extractOverrides(nodeItem["app_toml"])
+					if err != nil {
+						return err
+					}
 					chains[chainName].Nodes[nodeName] = &Node{
 						Validator:    validator,
 						Binary:       binary,
@@ -249,6 +379,10 @@ func (cfg *Config) CustomUnmarshal(data []byte) error {
 						Mnemonics:    mnemonics,
 						Port:         port,
 						Connections:  connections,
+						Balance:      balance,
+						Stake:        stake,
+						ConfigToml:   configToml,
+						AppToml:      appToml,
 					}
 				}
 			}
@@ -2,12 +2,22 @@ package config
 
 import (
 	"fmt"
+	"math/big"
 	"mvdan.cc/sh/v3/shell"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"tm/tm/v2/consts"
 	"tm/tm/v2/utils"
 	"tm/tm/v2/ux"
 )
 
+// denomPattern matches a denomination, e.g. uatom.
+var denomPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9/:._-]{2,127}$`)
+
+// coinPattern matches an amount with an optional denomination, e.g. 1000 or 1000uatom.
+var coinPattern = regexp.MustCompile(`^([0-9]+)([a-zA-Z][a-zA-Z0-9/:._-]{2,127})?$`)
+
 // validate checks the node logic in the configuration file.
 func (cfg *Config) validate() {
 
@@ -23,12 +33,17 @@ func (cfg *Config) validate() {
 		chain.HDPath = strings.TrimSpace(chain.HDPath)
 		chain.Binary = strings.TrimSpace(chain.Binary)
 		chain.Home = strings.TrimSpace(chain.Home)
-		chain.denom = strings.TrimSpace(chain.denom)
+		chain.Denom = strings.TrimSpace(chain.Denom)
+		chain.DisplayDenom = strings.TrimSpace(chain.DisplayDenom)
+		chain.Balance = strings.TrimSpace(chain.Balance)
+		chain.Stake = strings.TrimSpace(chain.Stake)
 		allChains = append(allChains, chainName)
 		for nodeName, node := range chain.Nodes {
 			node.Binary = strings.TrimSpace(node.Binary)
 			node.Home = strings.TrimSpace(node.Home)
 			node.Mnemonics = strings.TrimSpace(node.Mnemonics)
+			node.Balance = strings.TrimSpace(node.Balance)
+			node.Stake = strings.TrimSpace(node.Stake)
 			if node.Port > 65535 {
 				ux.Fatal("invalid port %s in chain %s node %s config", node.Port, chainName, nodeName)
 			}
@@ -74,6 +89,7 @@ func (cfg *Config) validate() {
 	// Chain IDs are inherently unique, no validation necessary.
 	// Node names are unique within a chain.
 	// Node names do not match chain IDs.
+	// Node names are not reserved table names.
 	// There is at least one validator per chain.
 	var allNodes []string
 	for chainID, chain := range cfg.Chains {
@@ -82,6 +98,9 @@ func (cfg *Config) validate() {
 			if utils.Contains(allChains, moniker) {
 				ux.Fatal("chain name and node name cannot both match %s", moniker)
 			}
+			if utils.Contains(ReservedNames, moniker) {
+				ux.Fatal("node name %s is reserved at %s definition", moniker, chainID)
+			}
 			nodeFullname := fmt.Sprintf("%s.%s", chainID, moniker)
 			if utils.Contains(allNodes, nodeFullname) {
 				ux.Fatal("duplicate node moniker %s.%s", chainID, moniker)
@@ -131,11 +150,57 @@ func (cfg *Config) validate() {
 		}
 	}
 
+	// Denominations are valid
+	// Decimals are set with a display denomination only, and a display denomination has decimals
+	for chainID, chain := range cfg.Chains {
+		validateDenom(chain.Denom, chainID)
+		validateDenom(chain.DisplayDenom, chainID)
+		if chain.Decimals > 0 && chain.DisplayDenom == "" {
+			ux.Fatal("decimals require a display denomination at %s definition", chainID)
+		}
+		if chain.DisplayDenom != "" && chain.Decimals == 0 {
+			ux.Fatal("display denomination requires decimals at %s definition", chainID)
+		}
+		if chain.DisplayDenom != "" && chain.DisplayDenom == chain.Denom {
+			ux.Fatal("display denomination cannot match the denomination at %s definition", chainID)
+		}
+	}
+
+	// Balances and stakes are lists of coins
+	// Stakes are one coin, set on validators only
+	// Stakes are in the chain denomination and covered by the balance
+	for _, wallet := range cfg.Wallets {
+		validateCoins(wallet.Balance, fmt.Sprintf("wallet %s", wallet.Name))
+	}
+	for chainID, chain := range cfg.Chains {
+		validateCoins(chain.Balance, chainID)
+		validateCoins(chain.Stake, chainID)
+		if strings.Contains(chain.Stake, ",") {
+			ux.Fatal("stake has to be one coin at %s definition", chainID)
+		}
+		for nodeMoniker, node := range chain.Nodes {
+			definition := fmt.Sprintf("%s.%s", chainID, nodeMoniker)
+			validateCoins(node.Balance, definition)
+			validateCoins(node.Stake, definition)
+			if strings.Contains(node.Stake, ",") {
+				ux.Fatal("stake has to be one coin at %s definition", definition)
+			}
+			if !node.Validator && (node.Balance != "" || node.Stake != "") {
+				ux.Fatal("balance and stake are only valid on validators at %s definition", definition)
+			}
+			if node.Validator {
+				validateStake(getSetting(consts.GenesisBalance, chain.Balance, node.Balance), getSetting(consts.GenesisStake, chain.Stake, node.Stake), chain.Denom, definition)
+			}
+		}
+	}
+
 	// Each Hermes config should have at least one node
 	// Hermes Config parameter is unique
+	// Hermes config folder is unique, because it is the Hermes home
 	// Hermes nodes connect to valid nodes only
 	// Hermes points to maximum one node per chain
 	var allHermesConfig []string
+	var allHermesHomes []string
 	for i, hermes := range cfg.Hermes {
 
 		if len(hermes.Nodes) == 0 {
@@ -146,31 +211,168 @@ func (cfg *Config) validate() {
 		if err != nil {
 			ux.Fatal("config cannot be expanded at %d.[[Hermes]] definition", i+1)
 		}
-		if utils.Contains(allHermesConfig, expanded) {
+		// An empty config path defaults to a unique path in the tm home.
+		if expanded != "" && utils.Contains(allHermesConfig, expanded) {
 			ux.Fatal("config path has to be unique at %d.[[Hermes]] definition", i+1)
 		}
 		allHermesConfig = append(allHermesConfig, expanded)
+		home := filepath.Clean(cfg.GetHermesHome(GetHermesName(i)))
+		if utils.Contains(allHermesHomes, home) {
+			ux.Fatal("config folder %s has to be unique at %d.[[Hermes]] definition", home, i+1)
+		}
+		allHermesHomes = append(allHermesHomes, home)
 
-		var allHermesNetworks []string
-		var connectionFullname string
-		for j, connection := range hermes.Nodes {
-			connectionFullname, err = utils.FindNodeFullname(allNodes, connection)
-			if err != nil {
-				ux.Fatal("%s at %d.[[Hermes]] definition", err.Error(), i+1)
-			}
-			hermes.Nodes[j] = connectionFullname
-			if !utils.Contains(allNodes, connectionFullname) {
-				ux.Fatal("non-existent connection %s at %d.[[Hermes]] definition", connection, i+1)
-			}
-			connectionFullnameSplit := strings.Split(connectionFullname, ".")
-			if len(connectionFullnameSplit) != 2 {
-				ux.Fatal("invalid connection name at %d.[[Hermes]] definition", connection, i+1)
-			}
-			connectionChainID := connectionFullnameSplit[0]
-			if utils.Contains(allHermesNetworks, connectionChainID) {
-				ux.Fatal("multiple node connection to %s chain at %d.[[Hermes]] definition", connectionChainID, i+1)
+		validateRelayerNodes(hermes.Nodes, allNodes, fmt.Sprintf("%d.[[Hermes]]", i+1))
+	}
+
+	// Each relayer config should have at least one node
+	// Relayer Home parameter is unique
+	// Relayer nodes connect to valid nodes only
+	// Relayer points to maximum one node per chain
+	var allRelayerHomes []string
+	for i := range cfg.Relayers {
+		relayer := &cfg.Relayers[i]
+		relayer.Binary = strings.TrimSpace(relayer.Binary)
+		relayer.Home = strings.TrimSpace(relayer.Home)
+		relayer.Mnemonics = strings.TrimSpace(relayer.Mnemonics)
+
+		if len(relayer.Nodes) == 0 {
+			ux.Fatal("no relayer nodes at %d.[[relayer]] definition", i+1)
+		}
+
+		expanded, err := shell.Expand(relayer.Home, nil)
+		if err != nil {
+			ux.Fatal("home cannot be expanded at %d.[[relayer]] definition", i+1)
+		}
+		// An empty home defaults to a unique path in the tm home.
+		if expanded != "" && utils.Contains(allRelayerHomes, expanded) {
+			ux.Fatal("home path has to be unique at %d.[[relayer]] definition", i+1)
+		}
+		allRelayerHomes = append(allRelayerHomes, expanded)
+
+		validateRelayerNodes(relayer.Nodes, allNodes, fmt.Sprintf("%d.[[relayer]]", i+1))
+	}
+
+	// Channels connect two different, existing chains
+	// Channels have a Hermes or Go relayer instance that connects both chains
+	// Channel port defaults to "transfer", version defaults to "ics20-1"
+	for i := range cfg.Channels {
+		channel := &cfg.Channels[i]
+		if len(channel.Chains) != 2 {
+			ux.Fatal("two chains required at %d.[[channel]] definition", i+1)
+		}
+		for j := range channel.Chains {
+			channel.Chains[j] = strings.TrimSpace(channel.Chains[j])
+			if !utils.Contains(allChains, channel.Chains[j]) {
+				ux.Fatal("non-existent chain %s at %d.[[channel]] definition", channel.Chains[j], i+1)
 			}
-			allHermesNetworks = append(allHermesNetworks, connectionChainID)
 		}
+		if channel.Chains[0] == channel.Chains[1] {
+			ux.Fatal("channel points to self at %d.[[channel]] definition", i+1)
+		}
+		if cfg.FindHermesForChains(channel.Chains[0], channel.Chains[1]) == "" && cfg.FindRelayerForChains(channel.Chains[0], channel.Chains[1]) == "" {
+			ux.Fatal("no Hermes or Go relayer instance connects %s and %s at %d.[[channel]] definition", channel.Chains[0], channel.Chains[1], i+1)
+		}
+		channel.Port = strings.TrimSpace(channel.Port)
+		if channel.Port == "" {
+			channel.Port = consts.DefaultIBCPort
+		}
+		channel.Version = strings.TrimSpace(channel.Version)
+		if channel.Version == "" {
+			channel.Version = consts.DefaultIBCVersion
+		}
+	}
+}
+
+// validateRelayerNodes checks that the relayer nodes exist and there is at most one node per chain. Node names are
+// replaced with their full names.
+func validateRelayerNodes(nodes []string, allNodes []string, definition string) {
+	var allRelayerNetworks []string
+	for j, connection := range nodes {
+		connectionFullname, err := utils.FindNodeFullname(allNodes, strings.TrimSpace(connection))
+		if err != nil {
+			ux.Fatal("%s at %s definition", err.Error(), definition)
+		}
+		nodes[j] = connectionFullname
+		if !utils.Contains(allNodes, connectionFullname) {
+			ux.Fatal("non-existent connection %s at %s definition", connection, definition)
+		}
+		connectionFullnameSplit := strings.Split(connectionFullname, ".")
+		if len(connectionFullnameSplit) != 2 {
+			ux.Fatal("invalid connection name %s at %s definition", connection, definition)
+		}
+		connectionChainID := connectionFullnameSplit[0]
+		if utils.Contains(allRelayerNetworks, connectionChainID) {
+			ux.Fatal("multiple node connection to %s chain at %s definition", connectionChainID, definition)
+		}
+		allRelayerNetworks = append(allRelayerNetworks, connectionChainID)
+	}
+}
+
+// validateDenom checks a denomination.
+func validateDenom(denom string, definition string) {
+	if denom != "" && !denomPattern.MatchString(denom) {
+		ux.Fatal("invalid denomination %s at %s definition", denom, definition)
+	}
+}
+
+// validateCoins checks a comma-separated list of coins.
+func validateCoins(coins string, definition string) {
+	if coins == "" {
+		return
+	}
+	for _, coin := range strings.Split(coins, ",") {
+		if !coinPattern.MatchString(strings.TrimSpace(coin)) {
+			ux.Fatal("invalid coin %s at %s definition", coin, definition)
+		}
+	}
+}
+
+// getSetting returns the last setting that is not empty.
+func getSetting(settings ...string) string {
+	result := ""
+	for _, setting := range settings {
+		if setting != "" {
+			result = setting
+		}
+	}
+	return result
+}
+
+// validateStake checks that the stake is in the chain denomination and not more than the balance in that denomination.
+// Amounts without a denomination are in the chain denomination, which is the bond denomination of the generated genesis
+// if it is not set.
+func validateStake(balance string, stake string, denom string, definition string) {
+	match := coinPattern.FindStringSubmatch(strings.TrimSpace(stake))
+	stakeAmount, _ := new(big.Int).SetString(match[1], 10)
+	stakeDenom := match[2]
+	if stakeDenom == "" {
+		stakeDenom = denom
+	}
+	if denom != "" && stakeDenom != denom {
+		ux.Fatal("stake denomination %s does not match the chain denomination %s at %s definition", stakeDenom, denom, definition)
+	}
+	unknownDenom := false
+	for _, coin := range strings.Split(balance, ",") {
+		match = coinPattern.FindStringSubmatch(strings.TrimSpace(coin))
+		balanceDenom := match[2]
+		if balanceDenom == "" {
+			balanceDenom = denom
+		}
+		if balanceDenom == "" {
+			unknownDenom = true
+		}
+		if balanceDenom != stakeDenom {
+			continue
+		}
+		balanceAmount, _ := new(big.Int).SetString(match[1], 10)
+		if balanceAmount.Cmp(stakeAmount) < 0 {
+			ux.Fatal("stake %s is more than the balance %s at %s definition", stake, balance, definition)
+		}
+		return
+	}
+	// Without a chain denomination, an amount without a denomination might be in the stake denomination.
+	if !unknownDenom {
+		ux.Fatal("balance %s has no %s to stake at %s definition", balance, stakeDenom, definition)
 	}
 }
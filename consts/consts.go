@@ -1,13 +1,21 @@
 package consts
 
 import (
+	"fmt"
+	"syscall"
+	"time"
 	"tm/tm/v2/utils"
 )
 
 const PidFilePath = "%s/pid"
 const LogFilePath = "%s/log"
+const RestartsFilePath = "%s/restarts"
+const StoppedFilePath = "%s/stopped"
 const MnemonicsDirPath = "%s/config/mnemonics"
 const MnemonicsPath = "%s/config/mnemonics/%s.json"
+const IBCDirPath = "%s/config/ibc"
+const ChannelDirPath = "%s/config/ibc/%s"
+const ChannelPath = "%s/config/ibc/%s/%s.json"
 
 func GetPid(home string) string {
 	return utils.GetSlashPath(PidFilePath, home)
@@ -17,6 +25,21 @@ func GetLog(home string) string {
 	return utils.GetSlashPath(LogFilePath, home)
 }
 
+// GetLogArchive returns the file of a log archived at the time.
+func GetLogArchive(home string, archived time.Time) string {
+	return fmt.Sprintf("%s.%s", GetLog(home), archived.Format(LogArchiveTimeFormat))
+}
+
+// GetRestarts returns the file where a supervisor records the restarts of a node.
+func GetRestarts(home string) string {
+	return utils.GetSlashPath(RestartsFilePath, home)
+}
+
+// GetStopped returns the marker file of a process that was stopped on purpose.
+func GetStopped(home string) string {
+	return utils.GetSlashPath(StoppedFilePath, home)
+}
+
 func GetMnemonicsDir(home string) string {
 	return utils.GetSlashPath(MnemonicsDirPath, home)
 }
@@ -25,4 +48,105 @@ func GetMnemonics(home string, shortNodeName string) string {
 	return utils.GetSlashPath(MnemonicsPath, home, shortNodeName)
 }
 
+// GetIBCDir returns the folder of the IBC channel details of a chain.
+func GetIBCDir(home string) string {
+	return utils.GetSlashPath(IBCDirPath, home)
+}
+
+// GetChannelDir returns the folder of the IBC channel details for a port.
+func GetChannelDir(home string, port string) string {
+	return utils.GetSlashPath(ChannelDirPath, home, port)
+}
+
+// GetChannel returns the file of the IBC channel details for a port and a counterparty chain.
+func GetChannel(home string, port string, counterpartyChainName string) string {
+	return utils.GetSlashPath(ChannelPath, home, port, counterpartyChainName)
+}
+
 const StartupWaitTime = 2
+
+// GenesisBalance is the default genesis balance of validators, relayers and wallets. Amounts without a denomination
+// are in the chain denomination.
+const GenesisBalance = "10000000000,10000000000samoleans"
+
+// GenesisStake is the default self-delegation of validators, in the chain denomination.
+const GenesisStake = "1000000000"
+
+// StartupTimeout is the default number of seconds to wait for a node to produce blocks.
+const StartupTimeout = 60
+
+// StartupPollInterval is the time between two RPC status queries while waiting for a node to produce blocks.
+const StartupPollInterval = 500 * time.Millisecond
+
+// StopTimeout is the default number of seconds to wait for a process to exit after each stop signal.
+const StopTimeout = 30
+
+// StopPollInterval is the time between two process checks while waiting for a process to exit.
+const StopPollInterval = 100 * time.Millisecond
+
+// SignalNames are the names of the signals used to stop a process.
+var SignalNames = map[syscall.Signal]string{
+	syscall.SIGINT:  "SIGINT",
+	syscall.SIGTERM: "SIGTERM",
+	syscall.SIGKILL: "SIGKILL",
+}
+
+// MaxRestarts is the default number of times a supervisor restarts a crashed node.
+const MaxRestarts = 5
+
+// RestartBackoff is the initial wait time before a supervisor restarts a crashed node. It doubles after each restart,
+// up to MaxRestartBackoff.
+const RestartBackoff = time.Second
+const MaxRestartBackoff = time.Minute
+
+// SupervisePollInterval is the time between two process checks of a supervisor.
+const SupervisePollInterval = time.Second
+
+// MaxHeightDivergence is the largest difference between the block heights of the nodes of a chain that is not reported
+// as a problem.
+const MaxHeightDivergence = 2
+
+const DefaultIBCPort = "transfer"
+const DefaultIBCVersion = "ics20-1"
+
+// TopRefreshInterval is the time between two refreshes of the dashboard.
+const TopRefreshInterval = time.Second
+
+// TopLogSize is the number of bytes read from the end of a log file for the dashboard log pane.
+const TopLogSize = 16 * 1024
+
+// LogSortWindow is the time log lines are held back to order them by timestamp when following logs.
+const LogSortWindow = time.Second
+
+// LogMaxSize is the default size in megabytes at which a running process log is archived and a new log is started.
+const LogMaxSize = 100
+
+// LogRetention is the default number of archived logs kept in a home folder.
+const LogRetention = 5
+
+// LogArchiveTimeFormat is the time format of the archived log file suffix. It sorts in time order.
+const LogArchiveTimeFormat = "20060102-150405.000"
+
+// HermesRestPort is the REST server port in the Hermes configuration. The REST server is disabled.
+const HermesRestPort = 3000
+
+// HermesTelemetryPort is the default telemetry port of the first Hermes instance.
+const HermesTelemetryPort = 3001
+
+// RelayerAPIPort is the API server port of the first Go relayer instance.
+const RelayerAPIPort = 5183
+
+// RelayerDebugPort is the debug server port of the first Go relayer instance. It serves the metrics.
+const RelayerDebugPort = 7597
+
+// HealthBlockWindow is the default time within which a healthy node has produced a new block.
+const HealthBlockWindow = 30 * time.Second
+
+// HealthRelayerWindow is the default time within which a healthy relayer instance has written to its log.
+const HealthRelayerWindow = 5 * time.Minute
+
+// HealthTimeout is the default time to wait for the network to become healthy.
+const HealthTimeout = 2 * time.Minute
+
+// HealthPollInterval is the time between two health checks while waiting for the network to become healthy.
+const HealthPollInterval = time.Second
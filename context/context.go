@@ -12,7 +12,11 @@ type Context struct {
 	AllNodeNames      []string
 	AllValidatorNames []string
 	AllChainNames     []string
+	AllHermesNames    []string
+	AllRelayerNames   []string
 	Input             []string
+	HermesInput       []string
+	RelayerInput      []string
 	Config            config.Config
 }
 
@@ -38,9 +42,17 @@ func New(args []string) Context {
 			}
 		}
 	}
-	// If no arguments were specified, run the command for all nodes.
+	for i := range ctx.Config.Hermes {
+		ctx.AllHermesNames = append(ctx.AllHermesNames, config.GetHermesName(i))
+	}
+	for i := range ctx.Config.Relayers {
+		ctx.AllRelayerNames = append(ctx.AllRelayerNames, config.GetRelayerName(i))
+	}
+	// If no arguments were specified, run the command for all nodes and relayer instances.
 	if allNodesArg {
 		ctx.Input = ctx.AllNodeNames
+		ctx.HermesInput = ctx.AllHermesNames
+		ctx.RelayerInput = ctx.AllRelayerNames
 	}
 	// Fill in Input based on the input.
 	for _, arg := range args {
@@ -51,6 +63,20 @@ func New(args []string) Context {
 			}
 			continue
 		}
+		// Input is a Hermes instance
+		if utils.Contains(ctx.AllHermesNames, arg) {
+			if !utils.Contains(ctx.HermesInput, arg) {
+				ctx.HermesInput = append(ctx.HermesInput, arg)
+			}
+			continue
+		}
+		// Input is a Go relayer instance
+		if utils.Contains(ctx.AllRelayerNames, arg) {
+			if !utils.Contains(ctx.RelayerInput, arg) {
+				ctx.RelayerInput = append(ctx.RelayerInput, arg)
+			}
+			continue
+		}
 		fullArgName, err := utils.FindNodeFullname(ctx.AllNodeNames, arg)
 		if err != nil {
 			ux.Fatal("invalid input %s", arg)
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package execute
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the command in its own process group, so signals sent to the tm process group, e.g. Ctrl-C, do not
+// reach it.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package execute
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the command in its own process group, so Ctrl-C in the console of tm does not reach it.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
@@ -2,6 +2,7 @@ package execute
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io/fs"
 	"io/ioutil"
@@ -12,10 +13,14 @@ import (
 	"syscall"
 	"time"
 	"tm/tm/v2/consts"
+	"tm/tm/v2/rpc"
 	"tm/tm/v2/utils"
 	"tm/tm/v2/ux"
 )
 
+// ErrNotProducingBlocks is returned when a node process is running but the block height does not advance.
+var ErrNotProducingBlocks = errors.New("started but not producing blocks")
+
 func execute(binary string, arg ...string) (string, error) {
 	return executeWithStdIn("", binary, arg...)
 }
@@ -165,52 +170,165 @@ func ShowNodeID(binary string, home string) string {
 	return output
 }
 
-func Start(binary string, home string) (int, error) {
+// Start starts a node in the background and writes its PID file. Use WaitForBlocks to check if the node is working.
+func Start(binary string, home string, rotation LogRotation) (int, error) {
 	arg := []string{"start", "--home", home}
+	cmd, _, err := spawn(binary, home, rotation, arg...)
+	if err != nil {
+		return 0, err
+	}
+	pid := cmd.Process.Pid
+	err = writePid(home, pid)
+	return pid, err
+}
+
+// WaitForBlocks polls the RPC status of a node until its latest block height advances. It returns an error if the
+// process stops or the node does not produce blocks until the deadline.
+func WaitForBlocks(home string, rpcPort uint, deadline time.Time) error {
+	var firstHeight *uint64
+	for {
+		if GetPid(home) == nil {
+			return fmt.Errorf("process stopped, see %s", consts.GetLog(home))
+		}
+		status, err := rpc.GetStatus(rpcPort)
+		if err != nil {
+			ux.Debug("RPC status query on port %d failed: %s", rpcPort, err)
+		} else {
+			height := status.SyncInfo.LatestBlockHeight
+			if firstHeight == nil {
+				firstHeight = &height
+			} else if height > *firstHeight {
+				ux.Debug("block height advanced from %d to %d on port %d", *firstHeight, height, rpcPort)
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return ErrNotProducingBlocks
+		}
+		time.Sleep(consts.StartupPollInterval)
+	}
+}
 
-	logfile, err := os.Create(consts.GetLog(home))
+// startProcess starts a binary in the background and returns its PID if the process is still running after
+// consts.StartupWaitTime seconds.
+func startProcess(binary string, home string, rotation LogRotation, arg ...string) (int, error) {
+	cmd, checker, err := spawn(binary, home, rotation, arg...)
 	if err != nil {
 		return 0, err
 	}
+	for i := 0; i < consts.StartupWaitTime; i++ {
+		time.Sleep(time.Second)
+		if len(checker) > 0 {
+			return 0, fmt.Errorf("PID %d stopped", cmd.Process.Pid)
+		}
+	}
+	pid := cmd.Process.Pid
+	err = writePid(home, pid)
+	return pid, err
+}
+
+// spawn starts a binary in the background with its output redirected to the log file in the home folder. The log of the
+// previous run is archived first. The returned channel receives a value when the process exits.
+func spawn(binary string, home string, rotation LogRotation, arg ...string) (*exec.Cmd, chan int, error) {
+	err := ArchiveLog(home, rotation.Retention)
+	if err != nil {
+		ux.Warn("could not archive %s: %s", consts.GetLog(home), err)
+	}
+	logfile, err := openLog(home, rotation)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	cmd := exec.Command(binary, arg...)
 	cmd.Env = os.Environ()
 	cmd.Dir = home
 	cmd.Stdout = logfile
 	cmd.Stderr = logfile
+	detach(cmd)
 	ux.Debug("%s %s\n", binary, strings.Join(arg, " "))
 	err = cmd.Start()
 	if err != nil {
-		return 0, err
+		_ = logfile.Close()
+		return nil, nil, err
 	}
 	checker := make(chan int, 1)
 	go startupChecker(checker, cmd.Process, logfile)
-	for i := 0; i < consts.StartupWaitTime; i++ {
-		time.Sleep(time.Second)
-		if len(checker) > 0 {
-			return 0, fmt.Errorf("PID %d stopped", cmd.Process.Pid)
-		}
-	}
-	pid := cmd.Process.Pid
+	return cmd, checker, nil
+}
+
+// writePid writes the PID file with the PID, the start time and the binary path of the process. The start time and
+// the binary are used to detect PID reuse. The stop marker is removed, because the process runs again.
+func writePid(home string, pid int) error {
+	_ = os.Remove(consts.GetStopped(home))
 	pidString := strconv.Itoa(pid)
-	err = ioutil.WriteFile(consts.GetPid(home), []byte(pidString), fs.ModePerm)
+	startTime, binary, err := getProcessInfo(pid)
+	if err != nil {
+		ux.Debug("could not get process information of %d: %s", pid, err)
+	}
+	data := fmt.Sprintf("%s\n%s\n%s\n", pidString, startTime, binary)
+	err = ioutil.WriteFile(consts.GetPid(home), []byte(data), fs.ModePerm)
 	ux.Debug("process %s started and written to %s", pidString, consts.GetPid(home))
-	return pid, err
+	return err
 }
 
-func Stop(home string) error {
+// Stop stops the process in the home folder. It sends SIGINT, then SIGTERM and SIGKILL if the process does not exit
+// within the timeout after each signal. It returns the name of the signal that stopped the process, or empty string if
+// the process was not running. The process is marked as stopped first, so a supervisor does not restart it.
+func Stop(home string, timeout time.Duration) (string, error) {
+	MarkStopped(home)
 	pid := GetPid(home)
+	if pid == nil {
+		return "", nil
+	}
 	process, err := os.FindProcess(*pid)
 	if err != nil {
-		_ = GetPid(home)
-		return nil
+		_ = os.Remove(consts.GetPid(home))
+		return "", nil
 	}
-	err = process.Signal(syscall.SIGINT)
+	_ = os.Remove(consts.GetPid(home))
+	for _, signal := range []syscall.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL} {
+		err = process.Signal(signal)
+		if err != nil {
+			if errors.Is(err, os.ErrProcessDone) {
+				return "", nil
+			}
+			_ = writePid(home, *pid)
+			return "", fmt.Errorf("could not %s PID %d", consts.SignalNames[signal], *pid)
+		}
+		ux.Debug("%s sent to PID %d", consts.SignalNames[signal], *pid)
+		if waitForExit(*pid, timeout) {
+			return consts.SignalNames[signal], nil
+		}
+	}
+	_ = writePid(home, *pid)
+	return "", fmt.Errorf("PID %d did not exit", *pid)
+}
+
+// MarkStopped records that the process in the home folder is stopped on purpose. A missing PID file does not mean that,
+// because the PID file of a crashed process is removed by any PID check.
+func MarkStopped(home string) {
+	err := ioutil.WriteFile(consts.GetStopped(home), nil, fs.ModePerm)
 	if err != nil {
-		return fmt.Errorf("could not SIGINT PID %d", pid)
+		ux.Warn("could not write %s: %s", consts.GetStopped(home), err)
+	}
+}
+
+// IsStopped returns true if the process in the home folder was stopped on purpose and not started since.
+func IsStopped(home string) bool {
+	_, err := os.Stat(consts.GetStopped(home))
+	return err == nil
+}
+
+// waitForExit polls the process until it exits or the timeout is reached. It returns true if the process exited.
+func waitForExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for IsRunning(pid) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(consts.StopPollInterval)
 	}
-	_ = GetPid(home)
-	return nil
+	return true
 }
 
 func Reset(binary string, home string) (string, error) {
@@ -0,0 +1,155 @@
+package execute
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"tm/tm/v2/ux"
+)
+
+// StartHermes starts a Hermes relayer instance in the background. The PID and log files are kept in the home folder.
+func StartHermes(binary string, configFile string, home string, rotation LogRotation) (int, error) {
+	arg := []string{"--config", configFile, "start"}
+	return startProcess(binary, home, rotation, arg...)
+}
+
+// HermesKeysAdd restores a key from mnemonics into the Hermes keystore of a chain. An existing key with the same name is
+// overwritten.
+func HermesKeysAdd(binary string, configFile string, chainID string, name string, hdpath string, mnemonics string) error {
+	mnemonicsFile, err := os.CreateTemp("", "tm-mnemonics-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(mnemonicsFile.Name())
+	}()
+	_, err = mnemonicsFile.WriteString(mnemonics)
+	_ = mnemonicsFile.Close()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"--config", configFile, "keys", "add", "--chain", chainID, "--key-name", name, "--mnemonic-file", mnemonicsFile.Name(), "--overwrite"}
+	if hdpath != "" {
+		args = append(args, "--hd-path", hdpath)
+	}
+	out, err := execute(binary, args...)
+	if err != nil {
+		return fmt.Errorf("%s", strings.Split(out, "\n")[0])
+	}
+	ux.Debug("successful key add %s to hermes keystore of %s", name, chainID)
+	return nil
+}
+
+// hermesResult is the last JSON line that Hermes prints in JSON output mode.
+type hermesResult struct {
+	Result interface{} `json:"result"`
+	Status string      `json:"status"`
+}
+
+// executeHermes runs a Hermes command in JSON output mode and returns the result of the command.
+func executeHermes(binary string, configFile string, arg ...string) (interface{}, error) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	arg = append([]string{"--config", configFile, "--json"}, arg...)
+	cmd := exec.Command(binary, arg...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	ux.Debug("%s %s\n", binary, strings.Join(arg, " "))
+	err := cmd.Run()
+
+	var result *hermesResult
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		var lineResult hermesResult
+		if json.Unmarshal([]byte(line), &lineResult) == nil && lineResult.Status != "" {
+			result = &lineResult
+		}
+	}
+	if result == nil {
+		if err != nil {
+			return nil, fmt.Errorf("%s", strings.Split(stderr.String(), "\n")[0])
+		}
+		return nil, fmt.Errorf("no result in hermes output")
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("%v", result.Result)
+	}
+	return result.Result, nil
+}
+
+// findJSONString returns the first string value for the key in a decoded JSON structure, searching depth-first.
+func findJSONString(data interface{}, key string) string {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		if result, ok := value[key].(string); ok {
+			return result
+		}
+		for _, item := range value {
+			if result := findJSONString(item, key); result != "" {
+				return result
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if result := findJSONString(item, key); result != "" {
+				return result
+			}
+		}
+	}
+	return ""
+}
+
+// findJSONSides returns the values of a key on the "a_side" and the "b_side" of a Hermes result.
+func findJSONSides(data interface{}, key string) (string, string) {
+	result, ok := data.(map[string]interface{})
+	if !ok {
+		return "", ""
+	}
+	return findJSONString(result["a_side"], key), findJSONString(result["b_side"], key)
+}
+
+// HermesCreateClient creates a client on the host chain that tracks the reference chain and returns the client ID.
+func HermesCreateClient(binary string, configFile string, hostChainID string, referenceChainID string) (string, error) {
+	result, err := executeHermes(binary, configFile, "create", "client", "--host-chain", hostChainID, "--reference-chain", referenceChainID)
+	if err != nil {
+		return "", err
+	}
+	clientID := findJSONString(result, "client_id")
+	if clientID == "" {
+		return "", fmt.Errorf("client ID not found in hermes output")
+	}
+	ux.Debug("successful client creation %s on %s", clientID, hostChainID)
+	return clientID, nil
+}
+
+// HermesCreateConnection creates a connection between two existing clients and returns the connection IDs on both
+// chains.
+func HermesCreateConnection(binary string, configFile string, chainIDA string, clientIDA string, clientIDB string) (string, string, error) {
+	result, err := executeHermes(binary, configFile, "create", "connection", "--a-chain", chainIDA, "--a-client", clientIDA, "--b-client", clientIDB)
+	if err != nil {
+		return "", "", err
+	}
+	connectionIDA, connectionIDB := findJSONSides(result, "connection_id")
+	if connectionIDA == "" || connectionIDB == "" {
+		return "", "", fmt.Errorf("connection IDs not found in hermes output")
+	}
+	ux.Debug("successful connection creation %s on %s", connectionIDA, chainIDA)
+	return connectionIDA, connectionIDB, nil
+}
+
+// HermesCreateChannel creates a channel on an existing connection and returns the channel IDs on both chains.
+func HermesCreateChannel(binary string, configFile string, chainIDA string, connectionIDA string, port string, version string) (string, string, error) {
+	result, err := executeHermes(binary, configFile, "create", "channel", "--a-chain", chainIDA, "--a-connection", connectionIDA, "--a-port", port, "--b-port", port, "--channel-version", version)
+	if err != nil {
+		return "", "", err
+	}
+	channelIDA, channelIDB := findJSONSides(result, "channel_id")
+	if channelIDA == "" || channelIDB == "" {
+		return "", "", fmt.Errorf("channel IDs not found in hermes output")
+	}
+	ux.Debug("successful channel creation %s on %s", channelIDA, chainIDA)
+	return channelIDA, channelIDB, nil
+}
@@ -0,0 +1,208 @@
+package execute
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"tm/tm/v2/ux"
+)
+
+type txAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type txEvent struct {
+	Type       string        `json:"type"`
+	Attributes []txAttribute `json:"attributes"`
+}
+
+type txLog struct {
+	Events []txEvent `json:"events"`
+}
+
+// txResponse is the part of the transaction broadcast output that tm uses.
+type txResponse struct {
+	Height string  `json:"height"`
+	TxHash string  `json:"txhash"`
+	Code   uint    `json:"code"`
+	RawLog string  `json:"raw_log"`
+	Logs   []txLog `json:"logs"`
+}
+
+type coin struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// IBCTransfer sends an ICS-20 transfer and returns the transaction hash and the packet sequence.
+func IBCTransfer(binary string, home string, node string, chainID string, from string, port string, channel string, receiver string, amount string) (string, string, error) {
+	args := []string{"tx", "ibc-transfer", "transfer", port, channel, receiver, amount, "--from", from, "--keyring-backend", "test", "--home", home, "--node", node, "--chain-id", chainID, "--broadcast-mode", "block", "--yes", "--output", "json"}
+
+	out, err := execute(binary, args...)
+	if err != nil {
+		return "", "", fmt.Errorf("%s", strings.Split(out, "\n")[0])
+	}
+	var response txResponse
+	err = json.Unmarshal([]byte(out), &response)
+	if err != nil {
+		return "", "", fmt.Errorf("could not unmarshal transaction output: %s", err)
+	}
+	if response.Code != 0 {
+		return response.TxHash, "", fmt.Errorf("transaction failed with code %d: %s", response.Code, response.RawLog)
+	}
+	for _, log := range response.Logs {
+		for _, event := range log.Events {
+			if event.Type != "send_packet" {
+				continue
+			}
+			for _, attribute := range event.Attributes {
+				if attribute.Key == "packet_sequence" {
+					ux.Debug("successful transfer %s from %s, sequence %s", amount, from, attribute.Value)
+					return response.TxHash, attribute.Value, nil
+				}
+			}
+		}
+	}
+	return response.TxHash, "", fmt.Errorf("packet sequence not found in transaction %s", response.TxHash)
+}
+
+// QueryPacketReceived returns true if the packet was received on the chain.
+func QueryPacketReceived(binary string, node string, port string, channel string, sequence string) (bool, error) {
+	args := []string{"query", "ibc", "channel", "packet-receipt", port, channel, sequence, "--node", node, "--output", "json"}
+
+	out, err := execute(binary, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s", strings.Split(out, "\n")[0])
+	}
+	var response struct {
+		Received bool `json:"received"`
+	}
+	err = json.Unmarshal([]byte(out), &response)
+	if err != nil {
+		return false, fmt.Errorf("could not unmarshal packet receipt: %s", err)
+	}
+	return response.Received, nil
+}
+
+// packetCommitmentNotFound matches the error of a packet commitment query when the commitment does not exist.
+var packetCommitmentNotFound = regexp.MustCompile(`(?i)packet commitment (hash )?not found`)
+
+// QueryPacketAcknowledged returns true if the packet commitment was removed from the sending chain, which happens when
+// the acknowledgement is relayed back.
+func QueryPacketAcknowledged(binary string, node string, port string, channel string, sequence string) (bool, error) {
+	args := []string{"query", "ibc", "channel", "packet-commitment", port, channel, sequence, "--node", node, "--output", "json"}
+
+	out, err := execute(binary, args...)
+	if err != nil {
+		if packetCommitmentNotFound.MatchString(out) {
+			return true, nil
+		}
+		return false, fmt.Errorf("%s", strings.Split(out, "\n")[0])
+	}
+	return false, nil
+}
+
+// QueryPacketAcknowledgement returns true if the receiving chain wrote the acknowledgement of the packet.
+func QueryPacketAcknowledgement(binary string, node string, port string, channel string, sequence string) (bool, error) {
+	args := []string{"query", "ibc", "channel", "packet-ack", port, channel, sequence, "--node", node, "--output", "json"}
+
+	out, err := execute(binary, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s", strings.Split(out, "\n")[0])
+	}
+	var response struct {
+		Acknowledgement string `json:"acknowledgement"`
+	}
+	err = json.Unmarshal([]byte(out), &response)
+	if err != nil {
+		return false, fmt.Errorf("could not unmarshal packet acknowledgement: %s", err)
+	}
+	return response.Acknowledgement != "", nil
+}
+
+// QueryConnectionChannel returns the ID of the newest open channel on the port of a connection and the ID of its
+// counterparty channel.
+func QueryConnectionChannel(binary string, node string, connection string, port string) (string, string, error) {
+	args := []string{"query", "ibc", "channel", "connections", connection, "--node", node, "--output", "json"}
+
+	out, err := execute(binary, args...)
+	if err != nil {
+		return "", "", fmt.Errorf("%s", strings.Split(out, "\n")[0])
+	}
+	var response struct {
+		Channels []struct {
+			State        string `json:"state"`
+			PortID       string `json:"port_id"`
+			ChannelID    string `json:"channel_id"`
+			Counterparty struct {
+				ChannelID string `json:"channel_id"`
+			} `json:"counterparty"`
+		} `json:"channels"`
+	}
+	err = json.Unmarshal([]byte(out), &response)
+	if err != nil {
+		return "", "", fmt.Errorf("could not unmarshal connection channels: %s", err)
+	}
+	// Channels are listed in key order, so the newest channel is found by its sequence (e.g. 10 in "channel-10").
+	channelID := ""
+	counterpartyChannelID := ""
+	newest := -1
+	for _, channel := range response.Channels {
+		if channel.PortID != port || channel.State != "STATE_OPEN" {
+			continue
+		}
+		sequence, err := strconv.Atoi(strings.TrimPrefix(channel.ChannelID, "channel-"))
+		if err == nil && sequence > newest {
+			newest = sequence
+			channelID = channel.ChannelID
+			counterpartyChannelID = channel.Counterparty.ChannelID
+		}
+	}
+	if channelID == "" {
+		return "", "", fmt.Errorf("no open %s channel on %s", port, connection)
+	}
+	return channelID, counterpartyChannelID, nil
+}
+
+// QueryDenomHash returns the IBC denomination of a denomination trace (e.g. "transfer/channel-0/stake").
+func QueryDenomHash(binary string, node string, trace string) (string, error) {
+	args := []string{"query", "ibc-transfer", "denom-hash", trace, "--node", node, "--output", "json"}
+
+	out, err := execute(binary, args...)
+	if err != nil {
+		return "", fmt.Errorf("%s", strings.Split(out, "\n")[0])
+	}
+	var response struct {
+		Hash string `json:"hash"`
+	}
+	err = json.Unmarshal([]byte(out), &response)
+	if err != nil {
+		return "", fmt.Errorf("could not unmarshal denom hash: %s", err)
+	}
+	return fmt.Sprintf("ibc/%s", response.Hash), nil
+}
+
+// QueryBalances returns the balances of an address in a comma-separated list of coins.
+func QueryBalances(binary string, node string, address string) (string, error) {
+	args := []string{"query", "bank", "balances", address, "--node", node, "--output", "json"}
+
+	out, err := execute(binary, args...)
+	if err != nil {
+		return "", fmt.Errorf("%s", strings.Split(out, "\n")[0])
+	}
+	var response struct {
+		Balances []coin `json:"balances"`
+	}
+	err = json.Unmarshal([]byte(out), &response)
+	if err != nil {
+		return "", fmt.Errorf("could not unmarshal balances: %s", err)
+	}
+	var result []string
+	for _, balance := range response.Balances {
+		result = append(result, fmt.Sprintf("%s%s", balance.Amount, balance.Denom))
+	}
+	return strings.Join(result, ","), nil
+}
@@ -0,0 +1,165 @@
+package execute
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"tm/tm/v2/consts"
+	"tm/tm/v2/ux"
+)
+
+// LogRotation defines when process logs are archived and how many archives are kept.
+type LogRotation struct {
+	// MaxSize is the size in bytes at which a running process log is archived. Zero disables rotation.
+	MaxSize uint64
+	// Retention is the number of archived logs kept.
+	Retention uint
+}
+
+// GetLogArchives returns the archived logs in the home folder, from the oldest to the newest.
+func GetLogArchives(home string) []string {
+	prefix := consts.GetLog(home) + "."
+	matches, _ := filepath.Glob(prefix + "*")
+	var result []string
+	for _, match := range matches {
+		if _, err := time.Parse(consts.LogArchiveTimeFormat, strings.TrimPrefix(match, prefix)); err == nil {
+			result = append(result, match)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ArchiveLog renames the log in the home folder to a time-stamped archive, if it is not empty. The oldest archives
+// over the retention are removed.
+func ArchiveLog(home string, retention uint) error {
+	logFile := consts.GetLog(home)
+	info, err := os.Stat(logFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if info.Size() == 0 {
+		return nil
+	}
+	// Logs archived within the same millisecond get the next free time stamp, so an archive is not overwritten.
+	archived := time.Now()
+	archive := consts.GetLogArchive(home, archived)
+	for _, statErr := os.Stat(archive); statErr == nil; _, statErr = os.Stat(archive) {
+		archived = archived.Add(time.Millisecond)
+		archive = consts.GetLogArchive(home, archived)
+	}
+	err = os.Rename(logFile, archive)
+	if err != nil {
+		return err
+	}
+	ux.Debug("log archived to %s", archive)
+	archives := GetLogArchives(home)
+	for len(archives) > int(retention) {
+		ux.Debug("removing archived log %s", archives[0])
+		_ = os.Remove(archives[0])
+		archives = archives[1:]
+	}
+	return nil
+}
+
+// openLog returns the writer for the output of a process started in the home folder. The output is piped through a
+// "tm log-writer" process that rotates the log by size. If that is not possible, the log file is written directly.
+func openLog(home string, rotation LogRotation) (*os.File, error) {
+	if rotation.MaxSize > 0 {
+		writer, err := startLogWriter(home, rotation)
+		if err == nil {
+			return writer, nil
+		}
+		ux.Debug("could not start log writer, log rotation disabled: %s", err)
+	}
+	return os.OpenFile(consts.GetLog(home), os.O_CREATE|os.O_WRONLY|os.O_APPEND, fs.ModePerm)
+}
+
+// startLogWriter starts a "tm log-writer" process for the home folder and returns the pipe it reads from.
+func startLogWriter(home string, rotation LogRotation) (*os.File, error) {
+	tm, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(tm, "log-writer", home,
+		"--max-size", strconv.FormatUint(rotation.MaxSize, 10),
+		"--retention", strconv.FormatUint(uint64(rotation.Retention), 10))
+	cmd.Stdin = reader
+	detach(cmd)
+	err = cmd.Start()
+	_ = reader.Close()
+	if err != nil {
+		_ = writer.Close()
+		return nil, err
+	}
+	// The log writer exits when the process closes its output.
+	go func() {
+		_, _ = cmd.Process.Wait()
+	}()
+	return writer, nil
+}
+
+// WriteLog copies the input to the log in the home folder until the input ends. The log is archived when it reaches
+// the maximum size, at a line boundary. Interrupts are ignored, so the output of the process is written until it
+// exits.
+func WriteLog(input io.Reader, home string, rotation LogRotation) error {
+	signal.Ignore(syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	logFile := consts.GetLog(home)
+	output, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, fs.ModePerm)
+	if err != nil {
+		return err
+	}
+	var size uint64
+	if info, statErr := output.Stat(); statErr == nil {
+		size = uint64(info.Size())
+	}
+	reader := bufio.NewReader(input)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if output != nil && rotation.MaxSize > 0 && size > 0 && size+uint64(len(line)) > rotation.MaxSize {
+				_ = output.Close()
+				_ = ArchiveLog(home, rotation.Retention)
+				output = nil
+				size = 0
+			}
+			if output == nil {
+				// Lines are dropped while the log cannot be opened, so the process does not block on a full pipe.
+				output, err = os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, fs.ModePerm)
+				if err != nil {
+					output = nil
+				}
+			}
+			if output != nil {
+				n, _ := output.Write(line)
+				size += uint64(n)
+			}
+		}
+		if readErr != nil {
+			if output != nil {
+				_ = output.Close()
+			}
+			if errors.Is(readErr, io.EOF) {
+				return nil
+			}
+			return readErr
+		}
+	}
+}
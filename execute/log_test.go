@@ -0,0 +1,150 @@
+package execute
+
+import (
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"tm/tm/v2/consts"
+)
+
+func writeFile(t *testing.T, file string, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(file, []byte(content), fs.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, file string) string {
+	t.Helper()
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestGetLogArchives(t *testing.T) {
+	home := t.TempDir()
+	archived := time.Date(2022, 5, 1, 12, 0, 0, 0, time.Local)
+	newest := consts.GetLogArchive(home, archived.Add(time.Hour))
+	oldest := consts.GetLogArchive(home, archived.Add(-time.Hour))
+	middle := consts.GetLogArchive(home, archived)
+	for _, file := range []string{newest, oldest, middle, consts.GetLog(home), consts.GetLog(home) + ".bak", consts.GetLog(home) + ".20220501"} {
+		writeFile(t, file, "line\n")
+	}
+
+	archives := GetLogArchives(home)
+	if expected := []string{oldest, middle, newest}; !reflect.DeepEqual(archives, expected) {
+		t.Errorf("archives %v, expected %v", archives, expected)
+	}
+	if archives := GetLogArchives(filepath.Join(home, "missing")); len(archives) != 0 {
+		t.Errorf("archives %v in a missing home", archives)
+	}
+}
+
+func TestArchiveLog(t *testing.T) {
+	home := t.TempDir()
+	// A missing or empty log is not archived.
+	if err := ArchiveLog(home, 2); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, consts.GetLog(home), "")
+	if err := ArchiveLog(home, 2); err != nil {
+		t.Fatal(err)
+	}
+	if archives := GetLogArchives(home); len(archives) != 0 {
+		t.Fatalf("empty log archived to %v", archives)
+	}
+
+	archived := time.Date(2022, 5, 1, 12, 0, 0, 0, time.Local)
+	var previous []string
+	for i := 0; i < 3; i++ {
+		file := consts.GetLogArchive(home, archived.Add(time.Duration(i)*time.Hour))
+		writeFile(t, file, fmt.Sprintf("archive %d\n", i))
+		previous = append(previous, file)
+	}
+	writeFile(t, consts.GetLog(home), "current\n")
+	if err := ArchiveLog(home, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// The oldest archives are removed first.
+	archives := GetLogArchives(home)
+	if len(archives) != 2 || archives[0] != previous[2] {
+		t.Fatalf("archives %v, expected %s and the new archive", archives, previous[2])
+	}
+	if content := readFile(t, archives[1]); content != "current\n" {
+		t.Errorf("new archive has %q", content)
+	}
+	if _, err := os.Stat(consts.GetLog(home)); !os.IsNotExist(err) {
+		t.Errorf("log not moved to the archive")
+	}
+}
+
+func TestWriteLog(t *testing.T) {
+	home := t.TempDir()
+	var lines []string
+	for i := 0; i < 10; i++ {
+		lines = append(lines, fmt.Sprintf("line %02d\n", i))
+	}
+	// Lines are 8 bytes, so 3 lines fit in a log.
+	err := WriteLog(strings.NewReader(strings.Join(lines, "")), home, LogRotation{MaxSize: 30, Retention: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archives := GetLogArchives(home)
+	if len(archives) != 3 {
+		t.Fatalf("%d archives, expected 3", len(archives))
+	}
+	for i, archive := range archives {
+		if content, expected := readFile(t, archive), strings.Join(lines[i*3:i*3+3], ""); content != expected {
+			t.Errorf("archive %d has %q, expected %q", i, content, expected)
+		}
+	}
+	if content := readFile(t, consts.GetLog(home)); content != lines[9] {
+		t.Errorf("log has %q, expected %q", content, lines[9])
+	}
+}
+
+func TestWriteLogRetention(t *testing.T) {
+	home := t.TempDir()
+	// An existing log counts towards the size, and a line over the maximum size is written to a log of its own.
+	writeFile(t, consts.GetLog(home), "existing\n")
+	input := "a line over the maximum size\nshort\nlast"
+	err := WriteLog(strings.NewReader(input), home, LogRotation{MaxSize: 10, Retention: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archives := GetLogArchives(home)
+	if len(archives) != 1 {
+		t.Fatalf("%d archives, expected 1", len(archives))
+	}
+	if content := readFile(t, archives[0]); content != "a line over the maximum size\n" {
+		t.Errorf("archive has %q", content)
+	}
+	if content := readFile(t, consts.GetLog(home)); content != "short\nlast" {
+		t.Errorf("log has %q", content)
+	}
+}
+
+func TestWriteLogWithoutRotation(t *testing.T) {
+	home := t.TempDir()
+	input := strings.Repeat("line\n", 100)
+	if err := WriteLog(strings.NewReader(input), home, LogRotation{}); err != nil {
+		t.Fatal(err)
+	}
+	if archives := GetLogArchives(home); len(archives) != 0 {
+		t.Errorf("archives %v without rotation", archives)
+	}
+	if content := readFile(t, consts.GetLog(home)); content != input {
+		t.Errorf("log has %d bytes, expected %d", len(content), len(input))
+	}
+}
@@ -2,55 +2,116 @@ package execute
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 	"tm/tm/v2/consts"
 	"tm/tm/v2/ux"
 )
 
+// ProcessUsage is the resource usage of a process.
+type ProcessUsage struct {
+	// CPUTime is the total user and system CPU time used by the process.
+	CPUTime time.Duration
+	// RSS is the resident memory size in bytes.
+	RSS uint64
+}
+
+// ErrStalePid is returned when the process holding the stored PID is not the process that was started by tm.
+var ErrStalePid = errors.New("stale PID file")
+
+// GetPid returns the PID of the running process in the home folder. Nil is returned if the process is not running or
+// the PID file is stale. PID files of processes that are not running are removed.
 func GetPid(home string) *int {
+	pid, err := CheckPid(home)
+	if errors.Is(err, ErrStalePid) {
+		ux.Warn("%s", err)
+	}
+	return pid
+}
+
+// CheckPid returns the PID of the running process in the home folder. Nil is returned if the process is not running.
+// ErrStalePid is returned if the process holding the PID has a different start time or binary than the process that
+// was started by tm. PID files of processes that are not running or are stale are removed.
+func CheckPid(home string) (*int, error) {
 	pidFile := consts.GetPid(home)
 	if _, err := os.Stat(pidFile); err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return nil
+			return nil, nil
 		}
 		ux.Fatal("could not check %s", pidFile)
 	}
 	if bytes, err := ioutil.ReadFile(pidFile); err == nil {
+		lines := strings.Split(string(bytes), "\n")
 		var pid int
-		pid, err = strconv.Atoi(strings.Split(string(bytes), "\n")[0])
+		pid, err = strconv.Atoi(lines[0])
 		if err != nil {
 			ux.Debug("invalid data in file %s", pidFile)
 			_ = os.Remove(pidFile)
-			return nil
+			return nil, nil
 		}
 		var process *os.Process
 		process, err = os.FindProcess(pid)
 		if err != nil {
 			ux.Debug("could not query process ID %d for %s", pid, pidFile)
 			_ = os.Remove(pidFile)
-			return nil
+			return nil, nil
 		}
 		err = process.Signal(syscall.Signal(0))
 		if err != nil {
 			if errors.Is(err, syscall.EPERM) {
 				ux.Debug("user does not own process %d", pid)
 				_ = os.Remove(pidFile)
-				return nil
+				return nil, nil
 			}
-			if errors.Is(err, os.ErrProcessDone) {
+			if errors.Is(err, os.ErrProcessDone) || errors.Is(err, syscall.ESRCH) {
 				ux.Debug("process %d is already done", pid)
 				_ = os.Remove(pidFile)
-				return nil
+				return nil, nil
 			}
 			ux.Debug("checking if process %d is running failed: %s", pid, err)
-			return nil
+			return nil, nil
 		}
-		return &pid
+		// PID files written by older versions only contain the PID.
+		if len(lines) >= 3 && lines[1] != "" {
+			startTime, binary, procErr := getProcessInfo(pid)
+			if procErr != nil {
+				ux.Debug("could not get process information of %d: %s", pid, procErr)
+			}
+			if procErr == nil && startTime != "" && (startTime != lines[1] || binary != lines[2]) {
+				_ = os.Remove(pidFile)
+				return nil, fmt.Errorf("%w %s, PID %d belongs to %s", ErrStalePid, pidFile, pid, binary)
+			}
+		}
+		return &pid, nil
 	}
 	ux.Fatal("could not read %s", pidFile)
-	return nil
+	return nil, nil
+}
+
+// ReadPid returns the PID stored in the home folder without checking or cleaning up the process. Nil is returned if
+// there is no valid PID file.
+func ReadPid(home string) *int {
+	bytes, err := ioutil.ReadFile(consts.GetPid(home))
+	if err != nil {
+		return nil
+	}
+	pid, err := strconv.Atoi(strings.Split(string(bytes), "\n")[0])
+	if err != nil {
+		return nil
+	}
+	return &pid
+}
+
+// IsRunning returns true if a process with the PID is alive.
+func IsRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
 }
@@ -0,0 +1,71 @@
+//go:build linux
+
+package execute
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is the USER_HZ value of /proc, which is 100 on all supported architectures.
+const clockTicks = 100
+
+// readProcStat returns the fields of /proc/<pid>/stat after the command name.
+func readProcStat(pid int) ([]string, error) {
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, err
+	}
+	// The second field is the command name in parentheses, which can contain spaces.
+	statString := string(stat)
+	fields := strings.Fields(statString[strings.LastIndex(statString, ")")+1:])
+	if len(fields) < 22 {
+		return nil, fmt.Errorf("invalid /proc/%d/stat", pid)
+	}
+	return fields, nil
+}
+
+// GetProcessUsage returns the CPU time used and the resident memory size of a process from /proc.
+func GetProcessUsage(pid int) (ProcessUsage, error) {
+	fields, err := readProcStat(pid)
+	if err != nil {
+		return ProcessUsage{}, err
+	}
+	// utime and stime are the 14th and 15th fields, rss is the 24th field.
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return ProcessUsage{}, err
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return ProcessUsage{}, err
+	}
+	rss, err := strconv.ParseUint(fields[21], 10, 64)
+	if err != nil {
+		return ProcessUsage{}, err
+	}
+	return ProcessUsage{
+		CPUTime: time.Duration(utime+stime) * time.Second / clockTicks,
+		RSS:     rss * uint64(os.Getpagesize()),
+	}, nil
+}
+
+// getProcessInfo returns the start time (in clock ticks after boot) and the binary path of a process from /proc.
+func getProcessInfo(pid int) (string, string, error) {
+	fields, err := readProcStat(pid)
+	if err != nil {
+		return "", "", err
+	}
+	// The start time is the 22nd field, the 20th after the command name.
+	binary, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	if err != nil {
+		return "", "", err
+	}
+	// The binary might have been rebuilt while the process was running.
+	binary = strings.TrimSuffix(binary, " (deleted)")
+	return fields[19], binary, nil
+}
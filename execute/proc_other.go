@@ -0,0 +1,15 @@
+//go:build !linux
+
+package execute
+
+import "errors"
+
+// getProcessInfo is only implemented on Linux. Process start time and binary are not verified on other systems.
+func getProcessInfo(pid int) (string, string, error) {
+	return "", "", nil
+}
+
+// GetProcessUsage is only implemented on Linux.
+func GetProcessUsage(pid int) (ProcessUsage, error) {
+	return ProcessUsage{}, errors.New("process usage is only supported on Linux")
+}
@@ -0,0 +1,82 @@
+package execute
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"tm/tm/v2/ux"
+)
+
+// StartRelayer starts a Go relayer instance in the background. The PID and log files are kept in the home folder. The
+// debug server with the metrics listens on the debug port.
+func StartRelayer(binary string, home string, debugPort uint, rotation LogRotation) (int, error) {
+	arg := []string{"start", "--home", home, "--debug-addr", fmt.Sprintf("127.0.0.1:%d", debugPort)}
+	return startProcess(binary, home, rotation, arg...)
+}
+
+// RelayerKeysAdd restores a key from mnemonics into the Go relayer keystore of a chain. The Go relayer only takes
+// mnemonics as a command-line argument, so the key is added with the chain binary to the test keyring that the Go
+// relayer reads, and the mnemonics are passed on the standard input. An existing key is kept.
+func RelayerKeysAdd(binary string, keyDir string, name string, hdpath string, mnemonics string) error {
+	if _, err := os.Stat(filepath.Join(keyDir, "keyring-test", name+".info")); err == nil {
+		ux.Debug("key %s already exists in relayer keystore %s", name, keyDir)
+		return nil
+	}
+	args := []string{"keys", "add", name, "--recover", "--keyring-backend", "test", "--keyring-dir", keyDir, "--output", "json"}
+	if hdpath != "" {
+		args = append(args, "--hd-path", hdpath)
+	}
+	out, err := executeWithStdIn(mnemonics, binary, args...)
+	if err != nil {
+		return fmt.Errorf("%s", strings.Split(out, "\n")[0])
+	}
+	ux.Debug("successful key add %s to relayer keystore %s", name, keyDir)
+	return nil
+}
+
+// RelayerPathEnd holds the client and the connection of one chain of a linked Go relayer path.
+type RelayerPathEnd struct {
+	ChainID      string `yaml:"chain-id"`
+	ClientID     string `yaml:"client-id"`
+	ConnectionID string `yaml:"connection-id"`
+}
+
+// RelayerLink creates clients, a connection and a channel on a Go relayer path. It returns the source and destination
+// ends that the Go relayer stored in its configuration file.
+func RelayerLink(binary string, home string, configFile string, path string, port string, version string) (*RelayerPathEnd, *RelayerPathEnd, error) {
+	args := []string{"tx", "link", path, "--src-port", port, "--dst-port", port, "--version", version, "--home", home}
+	out, err := execute(binary, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s", lastLine(out))
+	}
+
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	var relayerConfig struct {
+		Paths map[string]struct {
+			Src RelayerPathEnd `yaml:"src"`
+			Dst RelayerPathEnd `yaml:"dst"`
+		} `yaml:"paths"`
+	}
+	err = yaml.Unmarshal(data, &relayerConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not decode %s: %s", configFile, err)
+	}
+	ends, ok := relayerConfig.Paths[path]
+	if !ok || ends.Src.ConnectionID == "" || ends.Dst.ConnectionID == "" {
+		return nil, nil, fmt.Errorf("path %s not linked in %s", path, configFile)
+	}
+	ux.Debug("successful link of path %s, %s <-> %s", path, ends.Src.ConnectionID, ends.Dst.ConnectionID)
+	return &ends.Src, &ends.Dst, nil
+}
+
+// lastLine returns the last non-empty line of the output. The Go relayer logs before it prints the error.
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return lines[len(lines)-1]
+}
@@ -0,0 +1,201 @@
+package ibc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"tm/tm/v2/consts"
+	"tm/tm/v2/context"
+	"tm/tm/v2/execute"
+	"tm/tm/v2/utils"
+	"tm/tm/v2/ux"
+)
+
+// Connect creates clients, a connection and a channel between two chains through the relayer instance that connects
+// them. Hermes is used if an instance connects both chains, the Go relayer otherwise. The results are stored in both
+// chain homes. The result fails if the channel was not created.
+func Connect(ctx context.Context, chainA string, chainB string, port string, version string) ux.Result {
+	if !utils.Contains(ctx.AllChainNames, chainA) {
+		ux.Fatal("chain %s not found in config", chainA)
+	}
+	if !utils.Contains(ctx.AllChainNames, chainB) {
+		ux.Fatal("chain %s not found in config", chainB)
+	}
+	if chainA == chainB {
+		ux.Fatal("cannot connect chain %s to itself", chainA)
+	}
+	name := fmt.Sprintf("%s<->%s", chainA, chainB)
+	if channel := ctx.Config.GetChannel(chainA, chainB, port); channel != nil {
+		ux.Info("⚠ %s <-> %s skipped, %s already connected on %s.", chainA, chainB, port, channel.ChannelID)
+		return ux.Result{Name: name, Result: "skipped"}
+	}
+	channel, err := connect(ctx, chainA, chainB, port, version)
+	if err != nil {
+		ux.Info("✘ %s <-> %s not connected, %s.", chainA, chainB, err)
+		return ux.Result{Name: name, Result: ux.ResultFailed, Error: err.Error()}
+	}
+	ux.Info("✔ %s <-> %s connected, %s %s <-> %s.", chainA, chainB, port, channel.ChannelID, channel.CounterpartyChannelID)
+	return ux.Result{Name: name, Result: "connected"}
+}
+
+// ConnectConfigured sets up all channels defined in the configuration that are not set up yet and have their chains
+// running. It returns the result of each channel that was set up.
+func ConnectConfigured(ctx context.Context) []ux.Result {
+	var results []ux.Result
+	for _, channel := range ctx.Config.Channels {
+		chainA := channel.Chains[0]
+		chainB := channel.Chains[1]
+		if ctx.Config.GetChannel(chainA, chainB, channel.Port) != nil {
+			continue
+		}
+		nodes := getRelayerNodes(ctx, chainA, chainB)
+		if execute.GetPid(ctx.Config.GetHome(nodes[0])) == nil || execute.GetPid(ctx.Config.GetHome(nodes[1])) == nil {
+			continue
+		}
+		results = append(results, Connect(ctx, chainA, chainB, channel.Port, channel.Version))
+	}
+	return results
+}
+
+// getRelayerNodes returns the nodes that the relayer instance setting up channels between the chains uses, the node of
+// chainA first. Nil is returned if no instance connects the chains.
+func getRelayerNodes(ctx context.Context, chainA string, chainB string) []string {
+	if hermesName := ctx.Config.FindHermesForChains(chainA, chainB); hermesName != "" {
+		return []string{ctx.Config.GetHermesNode(hermesName, chainA), ctx.Config.GetHermesNode(hermesName, chainB)}
+	}
+	if relayerName := ctx.Config.FindRelayerForChains(chainA, chainB); relayerName != "" {
+		return []string{ctx.Config.GetRelayerNode(relayerName, chainA), ctx.Config.GetRelayerNode(relayerName, chainB)}
+	}
+	return nil
+}
+
+func connect(ctx context.Context, chainA string, chainB string, port string, version string) (*utils.Channel, error) {
+	nodes := getRelayerNodes(ctx, chainA, chainB)
+	if nodes == nil {
+		return nil, fmt.Errorf("no Hermes or Go relayer instance connects %s and %s", chainA, chainB)
+	}
+	for _, fullNodename := range nodes {
+		if execute.GetPid(ctx.Config.GetHome(fullNodename)) == nil {
+			return nil, fmt.Errorf("%s is not running", fullNodename)
+		}
+	}
+	var resultA *utils.Channel
+	var err error
+	if hermesName := ctx.Config.FindHermesForChains(chainA, chainB); hermesName != "" {
+		resultA, err = connectHermes(ctx, hermesName, chainA, chainB, port, version)
+	} else {
+		resultA, err = connectRelayer(ctx, ctx.Config.FindRelayerForChains(chainA, chainB), chainA, chainB, nodes[0], port, version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resultB := utils.Channel{
+		Port:                     port,
+		Version:                  version,
+		ClientID:                 resultA.CounterpartyClientID,
+		ConnectionID:             resultA.CounterpartyConnectionID,
+		ChannelID:                resultA.CounterpartyChannelID,
+		CounterpartyChainID:      chainA,
+		CounterpartyClientID:     resultA.ClientID,
+		CounterpartyConnectionID: resultA.ConnectionID,
+		CounterpartyChannelID:    resultA.ChannelID,
+	}
+	writeChannel(ctx, chainA, *resultA)
+	writeChannel(ctx, chainB, resultB)
+	return resultA, nil
+}
+
+// connectHermes creates the clients, the connection and the channel with Hermes and returns the channel on chainA.
+func connectHermes(ctx context.Context, hermesName string, chainA string, chainB string, port string, version string) (*utils.Channel, error) {
+	binary := ctx.Config.GetHermesBinary(hermesName)
+	configFile := ctx.Config.GetHermesConfig(hermesName)
+	if _, err := os.Stat(configFile); err != nil {
+		return nil, fmt.Errorf("%s config %s not found", hermesName, configFile)
+	}
+
+	clientA, err := execute.HermesCreateClient(binary, configFile, chainA, chainB)
+	if err != nil {
+		return nil, fmt.Errorf("client on %s not created: %s", chainA, err)
+	}
+	clientB, err := execute.HermesCreateClient(binary, configFile, chainB, chainA)
+	if err != nil {
+		return nil, fmt.Errorf("client on %s not created: %s", chainB, err)
+	}
+	connectionA, connectionB, err := execute.HermesCreateConnection(binary, configFile, chainA, clientA, clientB)
+	if err != nil {
+		return nil, fmt.Errorf("connection not created: %s", err)
+	}
+	channelA, channelB, err := execute.HermesCreateChannel(binary, configFile, chainA, connectionA, port, version)
+	if err != nil {
+		return nil, fmt.Errorf("channel not created: %s", err)
+	}
+	return &utils.Channel{
+		Port:                     port,
+		Version:                  version,
+		ClientID:                 clientA,
+		ConnectionID:             connectionA,
+		ChannelID:                channelA,
+		CounterpartyChainID:      chainB,
+		CounterpartyClientID:     clientB,
+		CounterpartyConnectionID: connectionB,
+		CounterpartyChannelID:    channelB,
+	}, nil
+}
+
+// connectRelayer links the Go relayer path between the chains and returns the channel on chainA. The channel IDs are
+// queried from chainA through its node, because the Go relayer stores only the clients and the connection of a path.
+func connectRelayer(ctx context.Context, relayerName string, chainA string, chainB string, nodeA string, port string, version string) (*utils.Channel, error) {
+	binary := ctx.Config.GetRelayerBinary(relayerName)
+	home := ctx.Config.GetRelayerHome(relayerName)
+	configFile := ctx.Config.GetRelayerConfig(relayerName)
+	if _, err := os.Stat(configFile); err != nil {
+		return nil, fmt.Errorf("%s config %s not found", relayerName, configFile)
+	}
+
+	path, reversed := ctx.Config.GetRelayerPath(relayerName, chainA, chainB)
+	endA, endB, err := execute.RelayerLink(binary, home, configFile, path, port, version)
+	if err != nil {
+		return nil, fmt.Errorf("path %s not linked: %s", path, err)
+	}
+	if reversed {
+		endA, endB = endB, endA
+	}
+	rpcA := fmt.Sprintf("tcp://127.0.0.1:%d", ctx.Config.GetRPCPort(nodeA))
+	channelA, channelB, err := execute.QueryConnectionChannel(ctx.Config.GetBinary(nodeA), rpcA, endA.ConnectionID, port)
+	if err != nil {
+		return nil, fmt.Errorf("channel not found: %s", err)
+	}
+	return &utils.Channel{
+		Port:                     port,
+		Version:                  version,
+		ClientID:                 endA.ClientID,
+		ConnectionID:             endA.ConnectionID,
+		ChannelID:                channelA,
+		CounterpartyChainID:      chainB,
+		CounterpartyClientID:     endB.ClientID,
+		CounterpartyConnectionID: endB.ConnectionID,
+		CounterpartyChannelID:    channelB,
+	}, nil
+}
+
+// writeChannel stores the channel details in the chain home.
+func writeChannel(ctx context.Context, chainName string, channel utils.Channel) {
+	home := ctx.Config.GetChainHome(chainName)
+	channelDir := consts.GetChannelDir(home, channel.Port)
+	err := os.MkdirAll(channelDir, fs.ModeDir|fs.ModePerm)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		ux.Fatal("could not create channel folder at %s", channelDir)
+	}
+	data, err := json.MarshalIndent(channel, "", "  ")
+	if err != nil {
+		ux.Fatal("could not encode channel %s on %s: %s", channel.ChannelID, chainName, err)
+	}
+	err = ioutil.WriteFile(consts.GetChannel(home, channel.Port, channel.CounterpartyChainID), data, fs.ModePerm)
+	if err != nil {
+		ux.Fatal("could not write channel %s on %s: %s", channel.ChannelID, chainName, err)
+	}
+}
@@ -0,0 +1,145 @@
+package ibc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"tm/tm/v2/context"
+	"tm/tm/v2/execute"
+	"tm/tm/v2/utils"
+	"tm/tm/v2/ux"
+)
+
+// Transfer sends an ICS-20 transfer from a wallet on the source chain to a wallet on the destination chain. It waits
+// until the packet is received and acknowledged or the timeout is reached, then reports the denomination trace and the
+// balances. The result fails if the packet was not sent, received or acknowledged.
+func Transfer(ctx context.Context, srcChain string, dstChain string, walletName string, receiverName string, amount string, port string, timeout time.Duration) ux.Result {
+	if !utils.Contains(ctx.AllChainNames, srcChain) {
+		ux.Fatal("chain %s not found in config", srcChain)
+	}
+	if !utils.Contains(ctx.AllChainNames, dstChain) {
+		ux.Fatal("chain %s not found in config", dstChain)
+	}
+	if receiverName == "" {
+		receiverName = walletName
+	}
+	for _, name := range []string{walletName, receiverName} {
+		found := false
+		for _, wallet := range ctx.Config.Wallets {
+			found = found || wallet.Name == name
+		}
+		if !found {
+			ux.Fatal("wallet %s not found in config", name)
+		}
+	}
+	channel := ctx.Config.GetChannel(srcChain, dstChain, port)
+	if channel == nil {
+		ux.Fatal("no %s channel between %s and %s, run tm ibc connect first", port, srcChain, dstChain)
+	}
+	srcNode := getRunningNode(ctx, srcChain)
+	if srcNode == "" {
+		ux.Fatal("no running node on %s", srcChain)
+	}
+	dstNode := getRunningNode(ctx, dstChain)
+	if dstNode == "" {
+		ux.Fatal("no running node on %s", dstChain)
+	}
+	receiver := ctx.Config.GetAddress(dstChain, receiverName)
+	if receiver == "" {
+		ux.Fatal("wallet %s not found on %s", receiverName, dstChain)
+	}
+	denom := strings.TrimLeft(amount, "0123456789")
+	if denom == "" {
+		denom = ctx.Config.GetDenom(srcChain)
+		amount = fmt.Sprintf("%s%s", amount, denom)
+	}
+
+	srcBinary := ctx.Config.GetChainBinary(srcChain)
+	dstBinary := ctx.Config.GetChainBinary(dstChain)
+	srcRPC := fmt.Sprintf("tcp://127.0.0.1:%d", ctx.Config.GetRPCPort(srcNode))
+	dstRPC := fmt.Sprintf("tcp://127.0.0.1:%d", ctx.Config.GetRPCPort(dstNode))
+
+	result := ux.Result{Name: fmt.Sprintf("%s->%s", srcChain, dstChain), Result: "acknowledged"}
+	txHash, sequence, err := execute.IBCTransfer(srcBinary, ctx.Config.GetChainHome(srcChain), srcRPC, srcChain, walletName, port, channel.ChannelID, receiver, amount)
+	if err != nil {
+		ux.Info("✘ %s %s -> %s not sent, %s.", amount, srcChain, dstChain, err)
+		result.Result = ux.ResultFailed
+		result.Error = fmt.Sprintf("not sent, %s", err)
+		return result
+	}
+	ux.Info("✔ %s %s -> %s sent on %s, sequence %s, tx %s.", amount, srcChain, dstChain, channel.ChannelID, sequence, txHash)
+
+	deadline := time.Now().Add(timeout)
+	received := false
+	acknowledged := false
+	for !acknowledged && time.Now().Before(deadline) {
+		time.Sleep(time.Second)
+		if !received {
+			received, err = execute.QueryPacketReceived(dstBinary, dstRPC, port, channel.CounterpartyChannelID, sequence)
+			if err != nil {
+				ux.Debug("packet receipt query failed on %s: %s", dstChain, err)
+			}
+			if received {
+				ux.Info("✔ packet %s received on %s.", sequence, dstChain)
+			}
+			continue
+		}
+		acknowledged, err = execute.QueryPacketAcknowledged(srcBinary, srcRPC, port, channel.ChannelID, sequence)
+		if err != nil {
+			ux.Debug("packet commitment query failed on %s: %s", srcChain, err)
+		}
+		// The acknowledgement written on the destination chain confirms that the commitment was removed by it.
+		if acknowledged {
+			acknowledged, err = execute.QueryPacketAcknowledgement(dstBinary, dstRPC, port, channel.CounterpartyChannelID, sequence)
+			if err != nil {
+				ux.Debug("packet acknowledgement query failed on %s: %s", dstChain, err)
+			}
+		}
+		if acknowledged {
+			ux.Info("✔ packet %s acknowledged on %s.", sequence, srcChain)
+		}
+	}
+	if !received {
+		ux.Info("✘ packet %s not received on %s in %s.", sequence, dstChain, timeout)
+		result.Result = ux.ResultFailed
+		result.Error = fmt.Sprintf("packet %s not received on %s in %s", sequence, dstChain, timeout)
+	} else if !acknowledged {
+		ux.Info("✘ packet %s not acknowledged on %s in %s.", sequence, srcChain, timeout)
+		result.Result = ux.ResultFailed
+		result.Error = fmt.Sprintf("packet %s not acknowledged on %s in %s", sequence, srcChain, timeout)
+	}
+
+	trace := fmt.Sprintf("%s/%s/%s", port, channel.CounterpartyChannelID, denom)
+	ibcDenom, err := execute.QueryDenomHash(dstBinary, dstRPC, trace)
+	if err != nil {
+		ux.Warn("could not query denom hash of %s on %s: %s", trace, dstChain, err)
+	} else {
+		ux.Info("denom trace: %s -> %s", trace, ibcDenom)
+	}
+	srcBalances, err := execute.QueryBalances(srcBinary, srcRPC, ctx.Config.GetAddress(srcChain, walletName))
+	if err != nil {
+		ux.Warn("could not query balances of %s on %s: %s", walletName, srcChain, err)
+	} else {
+		ux.Info("%s balance on %s: %s", walletName, srcChain, srcBalances)
+	}
+	dstBalances, err := execute.QueryBalances(dstBinary, dstRPC, receiver)
+	if err != nil {
+		ux.Warn("could not query balances of %s on %s: %s", receiverName, dstChain, err)
+	} else {
+		ux.Info("%s balance on %s: %s", receiverName, dstChain, dstBalances)
+	}
+	return result
+}
+
+// getRunningNode returns the first running node of a chain. Empty string is returned if no node is running.
+func getRunningNode(ctx context.Context, chainName string) string {
+	for _, fullNodename := range ctx.AllNodeNames {
+		if strings.Split(fullNodename, ".")[0] != chainName {
+			continue
+		}
+		if execute.GetPid(ctx.Config.GetHome(fullNodename)) != nil {
+			return fullNodename
+		}
+	}
+	return ""
+}
@@ -0,0 +1,259 @@
+package initialize
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"tm/tm/v2/config"
+	"tm/tm/v2/consts"
+	"tm/tm/v2/context"
+	"tm/tm/v2/execute"
+	"tm/tm/v2/ux"
+)
+
+// hermesGlobal defines the [global] section of the Hermes configuration.
+type hermesGlobal struct {
+	LogLevel string `toml:"log_level"`
+}
+
+type hermesModeClients struct {
+	Enabled      bool `toml:"enabled"`
+	Refresh      bool `toml:"refresh"`
+	Misbehaviour bool `toml:"misbehaviour"`
+}
+
+type hermesModeEnabled struct {
+	Enabled bool `toml:"enabled"`
+}
+
+type hermesModePackets struct {
+	Enabled        bool `toml:"enabled"`
+	ClearInterval  uint `toml:"clear_interval"`
+	ClearOnStart   bool `toml:"clear_on_start"`
+	TxConfirmation bool `toml:"tx_confirmation"`
+}
+
+// hermesMode defines the [mode] section of the Hermes configuration.
+type hermesMode struct {
+	Clients     hermesModeClients `toml:"clients"`
+	Connections hermesModeEnabled `toml:"connections"`
+	Channels    hermesModeEnabled `toml:"channels"`
+	Packets     hermesModePackets `toml:"packets"`
+}
+
+// hermesService defines the [rest] and [telemetry] sections of the Hermes configuration.
+type hermesService struct {
+	Enabled bool   `toml:"enabled"`
+	Host    string `toml:"host"`
+	Port    uint   `toml:"port"`
+}
+
+type hermesGasPrice struct {
+	Price float64 `toml:"price"`
+	Denom string  `toml:"denom"`
+}
+
+type hermesTrustThreshold struct {
+	Numerator   string `toml:"numerator"`
+	Denominator string `toml:"denominator"`
+}
+
+type hermesAddressType struct {
+	Derivation string `toml:"derivation"`
+}
+
+// hermesChain defines one [[chains]] entry of the Hermes configuration.
+type hermesChain struct {
+	ID             string               `toml:"id"`
+	RPCAddr        string               `toml:"rpc_addr"`
+	GRPCAddr       string               `toml:"grpc_addr"`
+	WebsocketAddr  string               `toml:"websocket_addr"`
+	RPCTimeout     string               `toml:"rpc_timeout"`
+	AccountPrefix  string               `toml:"account_prefix"`
+	KeyName        string               `toml:"key_name"`
+	StorePrefix    string               `toml:"store_prefix"`
+	DefaultGas     uint                 `toml:"default_gas"`
+	MaxGas         uint                 `toml:"max_gas"`
+	GasMultiplier  float64              `toml:"gas_multiplier"`
+	MaxMsgNum      uint                 `toml:"max_msg_num"`
+	MaxTxSize      uint                 `toml:"max_tx_size"`
+	ClockDrift     string               `toml:"clock_drift"`
+	MaxBlockTime   string               `toml:"max_block_time"`
+	TrustingPeriod string               `toml:"trusting_period"`
+	GasPrice       hermesGasPrice       `toml:"gas_price"`
+	TrustThreshold hermesTrustThreshold `toml:"trust_threshold"`
+	AddressType    hermesAddressType    `toml:"address_type"`
+}
+
+// hermesFile defines the Hermes configuration file format.
+type hermesFile struct {
+	Global    hermesGlobal  `toml:"global"`
+	Mode      hermesMode    `toml:"mode"`
+	Rest      hermesService `toml:"rest"`
+	Telemetry hermesService `toml:"telemetry"`
+	Chains    []hermesChain `toml:"chains"`
+}
+
+// createHermesConfigs writes the configuration file of all Hermes instances that connect to an initialized chain.
+func createHermesConfigs(ctx context.Context, doneNetworkNames []string) []ux.Result {
+	var results []ux.Result
+	for i, hermes := range ctx.Config.Hermes {
+		hermesName := config.GetHermesName(i)
+		if !connectsTo(hermes.Nodes, doneNetworkNames) {
+			continue
+		}
+		hermesCfg, err := newHermesFile(ctx, hermesName)
+		if err != nil {
+			ux.Warn("%s config not created: %s", hermesName, err)
+			results = append(results, ux.Result{Name: hermesName, Result: ux.ResultFailed, Error: err.Error()})
+			continue
+		}
+		var buf bytes.Buffer
+		err = toml.NewEncoder(&buf).Encode(hermesCfg)
+		if err != nil {
+			ux.Fatal("could not encode %s config: %s", hermesName, err)
+		}
+		configFile := ctx.Config.GetHermesConfig(hermesName)
+		err = os.MkdirAll(filepath.Dir(configFile), fs.ModeDir|fs.ModePerm)
+		if err != nil && !errors.Is(err, os.ErrExist) {
+			ux.Fatal("could not create %s config folder at %s", hermesName, filepath.Dir(configFile))
+		}
+		err = ioutil.WriteFile(configFile, buf.Bytes(), fs.ModePerm)
+		if err != nil {
+			ux.Fatal("could not write %s config, %s", hermesName, err.Error())
+		}
+		ux.Debug("successful config creation for %s at %s", hermesName, configFile)
+		addHermesKeys(ctx, hermesName)
+		results = append(results, ux.Result{Name: hermesName, Result: "initialized"})
+	}
+	return results
+}
+
+// addHermesKeys restores the Hermes wallet of each connected chain into the Hermes keystore.
+func addHermesKeys(ctx context.Context, hermesName string) {
+	_, hermes := ctx.Config.FindHermes(hermesName)
+	binary := ctx.Config.GetHermesBinary(hermesName)
+	configFile := ctx.Config.GetHermesConfig(hermesName)
+	for _, fullNodename := range hermes.Nodes {
+		chainName := strings.Split(fullNodename, ".")[0]
+		mnemonics := ctx.Config.GetMnemonics(chainName, hermesName)
+		// Recovered keys do not store their mnemonics in the chain home.
+		if mnemonics == "" {
+			mnemonics = hermes.Mnemonics
+		}
+		if mnemonics == "" {
+			ux.Warn("no mnemonics found for %s on chain %s", hermesName, chainName)
+			continue
+		}
+		err := execute.HermesKeysAdd(binary, configFile, chainName, hermesName, ctx.Config.Chains[chainName].HDPath, mnemonics)
+		if err != nil {
+			ux.Warn("could not add key %s to %s keystore for chain %s: %s", hermesName, hermesName, chainName, err)
+		}
+	}
+}
+
+// newHermesFile assembles the Hermes configuration from the tm config and the initialized chains.
+func newHermesFile(ctx context.Context, hermesName string) (hermesFile, error) {
+	_, hermes := ctx.Config.FindHermes(hermesName)
+	result := hermesFile{
+		Global: hermesGlobal{
+			LogLevel: ctx.Config.GetHermesLogLevel(hermesName),
+		},
+		Mode: hermesMode{
+			Clients: hermesModeClients{
+				Enabled:      true,
+				Refresh:      true,
+				Misbehaviour: true,
+			},
+			Connections: hermesModeEnabled{Enabled: false},
+			Channels:    hermesModeEnabled{Enabled: false},
+			Packets: hermesModePackets{
+				Enabled:        true,
+				ClearInterval:  100,
+				ClearOnStart:   true,
+				TxConfirmation: true,
+			},
+		},
+		Rest: hermesService{
+			Enabled: false,
+			Host:    "127.0.0.1",
+			Port:    consts.HermesRestPort,
+		},
+		Telemetry: hermesService{
+			Enabled: ctx.Config.GetHermesTelemetryEnabled(hermesName),
+			Host:    ctx.Config.GetHermesTelemetryHost(hermesName),
+			Port:    ctx.Config.GetHermesTelemetryPort(hermesName),
+		},
+	}
+	for _, fullNodename := range hermes.Nodes {
+		chainName := strings.Split(fullNodename, ".")[0]
+		if _, err := os.Stat(ctx.Config.GetChainPath(chainName, "config/genesis.json")); err != nil {
+			return result, fmt.Errorf("chain %s is not initialized", chainName)
+		}
+		accountPrefix, err := getAccountPrefix(ctx, chainName, hermesName)
+		if err != nil {
+			return result, err
+		}
+		result.Chains = append(result.Chains, hermesChain{
+			ID:             chainName,
+			RPCAddr:        fmt.Sprintf("http://127.0.0.1:%d", ctx.Config.GetRPCPort(fullNodename)),
+			GRPCAddr:       fmt.Sprintf("http://127.0.0.1:%d", ctx.Config.GetGRPCPort(fullNodename)),
+			WebsocketAddr:  fmt.Sprintf("ws://127.0.0.1:%d/websocket", ctx.Config.GetRPCPort(fullNodename)),
+			RPCTimeout:     "10s",
+			AccountPrefix:  accountPrefix,
+			KeyName:        hermesName,
+			StorePrefix:    "ibc",
+			DefaultGas:     100000,
+			MaxGas:         3000000,
+			GasMultiplier:  1.1,
+			MaxMsgNum:      30,
+			MaxTxSize:      2097152,
+			ClockDrift:     "5s",
+			MaxBlockTime:   "30s",
+			TrustingPeriod: "14days",
+			GasPrice: hermesGasPrice{
+				Price: 0.01,
+				Denom: ctx.Config.GetDenom(fullNodename),
+			},
+			TrustThreshold: hermesTrustThreshold{
+				Numerator:   "1",
+				Denominator: "3",
+			},
+			AddressType: hermesAddressType{
+				Derivation: "cosmos",
+			},
+		})
+	}
+	return result, nil
+}
+
+// getAccountPrefix returns the bech32 account prefix of a chain, based on the address of a wallet in the chain home.
+func getAccountPrefix(ctx context.Context, chainName string, walletName string) (string, error) {
+	address := ctx.Config.GetAddress(chainName, walletName)
+	if address == "" {
+		return "", fmt.Errorf("key %s not found on chain %s", walletName, chainName)
+	}
+	prefixLength := strings.LastIndex(address, "1")
+	if prefixLength < 1 {
+		return "", fmt.Errorf("invalid address %s on chain %s", address, chainName)
+	}
+	return address[:prefixLength], nil
+}
+
+// connectsTo returns true if any of the nodes is in one of the chains.
+func connectsTo(fullNodenames []string, chainNames []string) bool {
+	for _, fullNodename := range fullNodenames {
+		for _, chainName := range chainNames {
+			if strings.Split(fullNodename, ".")[0] == chainName {
+				return true
+			}
+		}
+	}
+	return false
+}
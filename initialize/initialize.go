@@ -6,7 +6,9 @@ import (
 	"io/fs"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
+	"tm/tm/v2/config"
 	"tm/tm/v2/consts"
 	"tm/tm/v2/context"
 	"tm/tm/v2/execute"
@@ -14,15 +16,24 @@ import (
 	"tm/tm/v2/ux"
 )
 
-func Initialize(ctx context.Context) {
+func Initialize(ctx context.Context) []ux.Result {
+	var results []ux.Result
 	var doneNetworkNames []string
+	var failedNetworkNames []string
 	for _, fullNodename := range ctx.Input {
 		fullNodenameSplit := strings.Split(fullNodename, ".")
 		chainName := fullNodenameSplit[0]
 
-		if !utils.Contains(doneNetworkNames, chainName) {
+		if !utils.Contains(doneNetworkNames, chainName) && !utils.Contains(failedNetworkNames, chainName) {
+			if err := checkGenesisOverrides(ctx, fullNodename); err != nil {
+				ux.Info("✘ %s not initialized, %s.", chainName, err)
+				results = append(results, ux.Result{Name: chainName, Result: ux.ResultFailed, Error: err.Error()})
+				failedNetworkNames = append(failedNetworkNames, chainName)
+				continue
+			}
 			runInit(ctx, fullNodename)
 			setDenomInChainGenesis(ctx, fullNodename)
+			setGenesisOverrides(ctx, fullNodename)
 			createWallets(ctx, fullNodename)
 			addGenesisAccounts(ctx, fullNodename)
 			createGentxTransactions(ctx, fullNodename)
@@ -32,8 +43,34 @@ func Initialize(ctx context.Context) {
 			copyGenesis(ctx, fullNodename)
 			configure(ctx, fullNodename)
 			doneNetworkNames = append(doneNetworkNames, chainName)
+			results = append(results, getInitializeResults(ctx, chainName)...)
+		}
+	}
+	results = append(results, createHermesConfigs(ctx, doneNetworkNames)...)
+	results = append(results, createRelayerConfigs(ctx, doneNetworkNames)...)
+	if ctx.Config.Prometheus {
+		results = append(results, createPrometheusConfig(ctx))
+	}
+	return results
+}
+
+// getInitializeResults returns the results of the nodes of an initialized chain, in order. Validators include their
+// address.
+func getInitializeResults(ctx context.Context, chainName string) []ux.Result {
+	var nodeNames []string
+	for nodeName := range ctx.Config.Chains[chainName].Nodes {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	var results []ux.Result
+	for _, nodeName := range nodeNames {
+		result := ux.Result{Name: fmt.Sprintf("%s.%s", chainName, nodeName), Result: "initialized"}
+		if ctx.Config.Chains[chainName].Nodes[nodeName].Validator {
+			result.Address = ctx.Config.GetAddress(chainName, nodeName)
 		}
+		results = append(results, result)
 	}
+	return results
 }
 
 func runInit(ctx context.Context, fullNodename string) {
@@ -80,9 +117,108 @@ func setDenomInChainGenesis(ctx context.Context, fullNodename string) {
 		Denom  string `json:"denom"`
 	}
 	utils.SetConfigEntry(chainGenesis, "app_state.gov.deposit_params.min_deposit", []fee{{Amount: "10000000", Denom: denom}})
-	utils.SetConfigEntry(chainGenesis, "app_state.liquidity.params.pool_creation_fee", []fee{{Amount: "40000000", Denom: denom}})
+	// Not all chains have the liquidity module.
+	if utils.HasConfigEntry(chainGenesis, "app_state.liquidity.params.pool_creation_fee") {
+		utils.SetConfigEntry(chainGenesis, "app_state.liquidity.params.pool_creation_fee", []fee{{Amount: "40000000", Denom: denom}})
+	}
 	utils.SetConfigEntry(chainGenesis, "app_state.mint.params.mint_denom", denom)
 	utils.SetConfigEntry(chainGenesis, "app_state.staking.params.bond_denom", denom)
+	setDenomMetadataInChainGenesis(ctx, fullNodename)
+}
+
+// setDenomMetadataInChainGenesis registers the display denomination of the chain in the bank denomination metadata.
+// Existing metadata of the chain denomination is replaced.
+func setDenomMetadataInChainGenesis(ctx context.Context, fullNodename string) {
+	chainName := strings.Split(fullNodename, ".")[0]
+	chain := ctx.Config.Chains[chainName]
+	if chain.DisplayDenom == "" {
+		return
+	}
+	chainGenesis := ctx.Config.GetChainPath(fullNodename, "config/genesis.json")
+	if !utils.HasConfigEntry(chainGenesis, "app_state.bank.denom_metadata") {
+		ux.Warn("no denomination metadata in %s genesis, display denomination %s not registered", chainName, chain.DisplayDenom)
+		return
+	}
+	type denomUnit struct {
+		Denom    string   `json:"denom"`
+		Exponent uint     `json:"exponent"`
+		Aliases  []string `json:"aliases"`
+	}
+	type denomMetadata struct {
+		Description string      `json:"description"`
+		DenomUnits  []denomUnit `json:"denom_units"`
+		Base        string      `json:"base"`
+		Display     string      `json:"display"`
+		Name        string      `json:"name"`
+		Symbol      string      `json:"symbol"`
+	}
+	denom := ctx.Config.GetDenom(fullNodename)
+	var metadata []interface{}
+	if existing, ok := utils.GetConfigEntry(chainGenesis, "app_state.bank.denom_metadata").([]interface{}); ok {
+		for _, item := range existing {
+			if itemMap, ok2 := item.(map[string]interface{}); ok2 && itemMap["base"] == denom {
+				continue
+			}
+			metadata = append(metadata, item)
+		}
+	}
+	metadata = append(metadata, denomMetadata{
+		Description: fmt.Sprintf("The native token of %s", chainName),
+		DenomUnits: []denomUnit{
+			{Denom: denom, Exponent: 0, Aliases: []string{}},
+			{Denom: chain.DisplayDenom, Exponent: chain.Decimals, Aliases: []string{}},
+		},
+		Base:    denom,
+		Display: chain.DisplayDenom,
+		Name:    chain.DisplayDenom,
+		Symbol:  strings.ToUpper(chain.DisplayDenom),
+	})
+	utils.SetConfigEntry(chainGenesis, "app_state.bank.denom_metadata", metadata)
+}
+
+// checkGenesisOverrides checks that the genesis overrides of the chain exist in a genesis generated by the chain binary
+// in a temporary folder. Only existing paths can be overridden, so a mistyped path is not silently added. The check
+// runs before anything is written, so the chain is not left half-initialized.
+func checkGenesisOverrides(ctx context.Context, fullNodename string) error {
+	chainName := strings.Split(fullNodename, ".")[0]
+	overrides := ctx.Config.Chains[chainName].Genesis
+	if len(overrides) == 0 {
+		return nil
+	}
+	home, err := ioutil.TempDir("", "tm-genesis")
+	if err != nil {
+		return fmt.Errorf("could not create temporary folder: %s", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(home)
+	}()
+	execute.Init(fullNodename, ctx.Config.GetChainBinary(fullNodename), home)
+	genesis := utils.GetSlashPath("%s/config/genesis.json", home)
+	if _, err = os.Stat(genesis); err != nil {
+		return fmt.Errorf("could not generate a genesis to check the overrides: %s", err)
+	}
+	var missing []string
+	for key := range overrides {
+		if !utils.HasConfigEntry(genesis, key) {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("genesis path(s) not found: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// setGenesisOverrides sets the genesis overrides of the chain in the chain genesis. The paths were checked by
+// checkGenesisOverrides.
+func setGenesisOverrides(ctx context.Context, fullNodename string) {
+	chainName := strings.Split(fullNodename, ".")[0]
+	overrides := ctx.Config.Chains[chainName].Genesis
+	if len(overrides) == 0 {
+		return
+	}
+	setOverrides(ctx.Config.GetChainPath(fullNodename, "config/genesis.json"), overrides)
 }
 
 func createWallets(ctx context.Context, fullNodename string) {
@@ -102,7 +238,13 @@ func createWallets(ctx context.Context, fullNodename string) {
 	for i, hermes := range ctx.Config.Hermes {
 		binary := ctx.Config.GetChainBinary(chainName)
 		home := ctx.Config.GetChainHome(chainName)
-		execute.KeysAdd(binary, home, fmt.Sprintf("hermes%d", i), hdpath, hermes.Mnemonics)
+		execute.KeysAdd(binary, home, config.GetHermesName(i), hdpath, hermes.Mnemonics)
+	}
+	// Create keys for all Go relayer instances
+	for i, relayer := range ctx.Config.Relayers {
+		binary := ctx.Config.GetChainBinary(chainName)
+		home := ctx.Config.GetChainHome(chainName)
+		execute.KeysAdd(binary, home, config.GetRelayerName(i), hdpath, relayer.Mnemonics)
 	}
 	// Create keys for all wallets
 	for _, wallet := range ctx.Config.Wallets {
@@ -121,8 +263,7 @@ func addGenesisAccounts(ctx context.Context, fullNodename string) {
 		if nodeLoop.Validator {
 			binary := ctx.Config.GetChainBinary(fmt.Sprintf("%s.%s", chainName, nodeNameLoop))
 			home := ctx.Config.GetChainHome(fmt.Sprintf("%s.%s", chainName, nodeNameLoop))
-			denom := ctx.Config.GetDenom(chainName)
-			execute.AddGenesisAccount(binary, home, nodeNameLoop, fmt.Sprintf("10000000000%s,10000000000samoleans", denom))
+			execute.AddGenesisAccount(binary, home, nodeNameLoop, ctx.Config.GetBalance(fmt.Sprintf("%s.%s", chainName, nodeNameLoop)))
 		}
 	}
 
@@ -131,16 +272,21 @@ func addGenesisAccounts(ctx context.Context, fullNodename string) {
 	for i := range ctx.Config.Hermes {
 		binary := ctx.Config.GetChainBinary(chainName)
 		home := ctx.Config.GetChainHome(chainName)
-		denom := ctx.Config.GetDenom(chainName)
-		execute.AddGenesisAccount(binary, home, fmt.Sprintf("hermes%d", i), fmt.Sprintf("10000000000%s,10000000000samoleans", denom))
+		execute.AddGenesisAccount(binary, home, config.GetHermesName(i), ctx.Config.GetChainBalance(chainName))
+	}
+
+	// Create account for all Go relayer instances on all initializing networks
+	for i := range ctx.Config.Relayers {
+		binary := ctx.Config.GetChainBinary(chainName)
+		home := ctx.Config.GetChainHome(chainName)
+		execute.AddGenesisAccount(binary, home, config.GetRelayerName(i), ctx.Config.GetChainBalance(chainName))
 	}
 
 	// Create account for all wallets
 	for _, wallet := range ctx.Config.Wallets {
 		binary := ctx.Config.GetChainBinary(chainName)
 		home := ctx.Config.GetChainHome(chainName)
-		denom := ctx.Config.GetDenom(chainName)
-		execute.AddGenesisAccount(binary, home, wallet.Name, fmt.Sprintf("10000000000%s,10000000000samoleans", denom))
+		execute.AddGenesisAccount(binary, home, wallet.Name, ctx.Config.GetWalletBalance(chainName, wallet.Name))
 	}
 }
 
@@ -152,8 +298,7 @@ func createGentxTransactions(ctx context.Context, fullNodename string) {
 		if nodeLoop.Validator {
 			binary := ctx.Config.GetChainBinary(fmt.Sprintf("%s.%s", chainName, nodeNameLoop))
 			home := ctx.Config.GetChainHome(fmt.Sprintf("%s.%s", chainName, nodeNameLoop))
-			denom := ctx.Config.GetDenom(chainName)
-			execute.AddGentx(binary, home, chainName, nodeNameLoop, fmt.Sprintf("1000000000%s", denom))
+			execute.AddGentx(binary, home, chainName, nodeNameLoop, ctx.Config.GetStake(fmt.Sprintf("%s.%s", chainName, nodeNameLoop)))
 		}
 	}
 }
@@ -204,40 +349,71 @@ func copyGenesis(ctx context.Context, fullNodename string) {
 	}
 }
 
-func configure(ctx context.Context, fullNodename string) {
-	fullNodenameSplit := strings.Split(fullNodename, ".")
-	chainName := fullNodenameSplit[0]
-
-	for nodeName := range ctx.Config.Chains[chainName].Nodes {
-		fullNodename = fmt.Sprintf("%s.%s", chainName, nodeName)
-
-		// config.toml settings
-		configToml := ctx.Config.GetPath(fullNodename, "config/config.toml")
-		p2pAddress := fmt.Sprintf("tcp://0.0.0.0:%d", ctx.Config.GetP2PPort(fullNodename))
-		rpcAddress := fmt.Sprintf("tcp://0.0.0.0:%d", ctx.Config.GetRPCPort(fullNodename))
-		pprofAddress := fmt.Sprintf("0.0.0.0:%d", ctx.Config.GetPPROFPort(fullNodename))
-		utils.SetConfigEntry(configToml, "p2p.laddr", p2pAddress)
-		utils.SetConfigEntry(configToml, "rpc.laddr", rpcAddress)
-		utils.SetConfigEntry(configToml, "rpc.pprof_laddr", pprofAddress)
-
-		// app.toml settings
-		appToml := ctx.Config.GetPath(fullNodename, "config/app.toml")
-		appAddress := fmt.Sprintf("tcp://0.0.0.0:%d", ctx.Config.GetAppPort(fullNodename))
-		grpcAddress := fmt.Sprintf("0.0.0.0:%d", ctx.Config.GetGRPCPort(fullNodename))
-		grpcWebAddress := fmt.Sprintf("0.0.0.0:%d", ctx.Config.GetGRPCWEBPort(fullNodename))
-		minimumGasPrices := fmt.Sprintf("0%s", ctx.Config.GetDenom(fullNodename))
-
-		utils.SetConfigEntry(appToml, "minimum-gas-prices", minimumGasPrices)
-		utils.SetConfigEntry(appToml, "api.address", appAddress)
-		utils.SetConfigEntry(appToml, "api.enable", true)
-		utils.SetConfigEntry(appToml, "api.swagger", true)
-		utils.SetConfigEntry(appToml, "grpc.address", grpcAddress)
-		utils.SetConfigEntry(appToml, "grpc-web.address", grpcWebAddress)
-
-		if ctx.Config.GetStopMaintain(fullNodename) {
+// Configure reapplies the config.toml and app.toml settings of the nodes in the input, including the overrides in
+// the config, without re-initializing the chain.
+func Configure(ctx context.Context) []ux.Result {
+	var results []ux.Result
+	for _, fullNodename := range ctx.Input {
+		if err := checkConfigFiles(ctx, fullNodename); err != nil {
+			ux.Warn("%s is not initialized, %s", fullNodename, err)
+			results = append(results, ux.Result{Name: fullNodename, Result: ux.ResultFailed, Error: fmt.Sprintf("not initialized, %s", err)})
 			continue
 		}
+		configureNode(ctx, fullNodename)
+		ux.Info("✔ %s configured.", fullNodename)
+		results = append(results, ux.Result{Name: fullNodename, Result: "configured"})
+	}
+	return results
+}
 
+// checkConfigFiles returns an error if the config.toml or app.toml of the node does not exist.
+func checkConfigFiles(ctx context.Context, fullNodename string) error {
+	for _, file := range []string{"config/config.toml", "config/app.toml"} {
+		if _, err := os.Stat(ctx.Config.GetPath(fullNodename, file)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configure sets the config.toml and app.toml settings of all nodes of the chain.
+func configure(ctx context.Context, fullNodename string) {
+	chainName := strings.Split(fullNodename, ".")[0]
+	for nodeName := range ctx.Config.Chains[chainName].Nodes {
+		configureNode(ctx, fmt.Sprintf("%s.%s", chainName, nodeName))
+	}
+}
+
+// configureNode sets the listen addresses, peers and the API and gRPC settings of a node, then applies the overrides
+// in the config.
+func configureNode(ctx context.Context, fullNodename string) {
+	// config.toml settings
+	configToml := ctx.Config.GetPath(fullNodename, "config/config.toml")
+	p2pAddress := fmt.Sprintf("tcp://0.0.0.0:%d", ctx.Config.GetP2PPort(fullNodename))
+	rpcAddress := fmt.Sprintf("tcp://0.0.0.0:%d", ctx.Config.GetRPCPort(fullNodename))
+	pprofAddress := fmt.Sprintf("0.0.0.0:%d", ctx.Config.GetPPROFPort(fullNodename))
+	prometheusAddress := fmt.Sprintf("0.0.0.0:%d", ctx.Config.GetPrometheusPort(fullNodename))
+	utils.SetConfigEntry(configToml, "p2p.laddr", p2pAddress)
+	utils.SetConfigEntry(configToml, "rpc.laddr", rpcAddress)
+	utils.SetConfigEntry(configToml, "rpc.pprof_laddr", pprofAddress)
+	utils.SetConfigEntry(configToml, "instrumentation.prometheus", ctx.Config.Prometheus)
+	utils.SetConfigEntry(configToml, "instrumentation.prometheus_listen_addr", prometheusAddress)
+
+	// app.toml settings
+	appToml := ctx.Config.GetPath(fullNodename, "config/app.toml")
+	appAddress := fmt.Sprintf("tcp://0.0.0.0:%d", ctx.Config.GetAppPort(fullNodename))
+	grpcAddress := fmt.Sprintf("0.0.0.0:%d", ctx.Config.GetGRPCPort(fullNodename))
+	grpcWebAddress := fmt.Sprintf("0.0.0.0:%d", ctx.Config.GetGRPCWEBPort(fullNodename))
+	minimumGasPrices := fmt.Sprintf("0%s", ctx.Config.GetDenom(fullNodename))
+
+	utils.SetConfigEntry(appToml, "minimum-gas-prices", minimumGasPrices)
+	utils.SetConfigEntry(appToml, "api.address", appAddress)
+	utils.SetConfigEntry(appToml, "api.enable", true)
+	utils.SetConfigEntry(appToml, "api.swagger", true)
+	utils.SetConfigEntry(appToml, "grpc.address", grpcAddress)
+	utils.SetConfigEntry(appToml, "grpc-web.address", grpcWebAddress)
+
+	if !ctx.Config.GetStopMaintain(fullNodename) {
 		var peers []string
 		var peerIDs []string
 		for _, fullNodenameLoop := range ctx.Config.GetConnections(fullNodename) {
@@ -249,4 +425,21 @@ func configure(ctx context.Context, fullNodename string) {
 		utils.SetConfigEntry(configToml, "p2p.unconditional_peer_ids", strings.Join(peerIDs, ","))
 		utils.SetConfigEntry(configToml, "p2p.external_address", fmt.Sprintf("127.0.0.1:%d", ctx.Config.GetP2PPort(fullNodename)))
 	}
+
+	// Overrides are applied last, so they can replace any of the settings above.
+	setOverrides(configToml, ctx.Config.GetConfigTomlOverrides(fullNodename))
+	setOverrides(appToml, ctx.Config.GetAppTomlOverrides(fullNodename))
+}
+
+// setOverrides sets the dotted key entries in the file, in key order.
+func setOverrides(file string, overrides map[string]interface{}) {
+	var keys []string
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		ux.Debug("setting %s to %v in %s", key, overrides[key], file)
+		utils.SetConfigEntry(file, key, overrides[key])
+	}
 }
@@ -0,0 +1,112 @@
+package initialize
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"tm/tm/v2/config"
+	"tm/tm/v2/context"
+	"tm/tm/v2/ux"
+)
+
+// prometheusGlobal defines the global section of the Prometheus configuration.
+type prometheusGlobal struct {
+	ScrapeInterval string `yaml:"scrape_interval"`
+}
+
+// prometheusStaticConfig defines a group of targets with the same labels.
+type prometheusStaticConfig struct {
+	Targets []string          `yaml:"targets"`
+	Labels  map[string]string `yaml:"labels"`
+}
+
+// prometheusScrapeConfig defines one scrape_configs entry of the Prometheus configuration.
+type prometheusScrapeConfig struct {
+	JobName       string                   `yaml:"job_name"`
+	MetricsPath   string                   `yaml:"metrics_path,omitempty"`
+	StaticConfigs []prometheusStaticConfig `yaml:"static_configs"`
+}
+
+// prometheusFile defines the Prometheus configuration file format.
+type prometheusFile struct {
+	Global        prometheusGlobal         `yaml:"global"`
+	ScrapeConfigs []prometheusScrapeConfig `yaml:"scrape_configs"`
+}
+
+// createPrometheusConfig writes the Prometheus scrape configuration of all nodes and relayer instances in the config.
+// Each target is labelled with its chain and node or relayer name.
+func createPrometheusConfig(ctx context.Context) ux.Result {
+	nodes := prometheusScrapeConfig{JobName: "tendermint"}
+	var chainNames []string
+	for chainName := range ctx.Config.Chains {
+		chainNames = append(chainNames, chainName)
+	}
+	sort.Strings(chainNames)
+	for _, chainName := range chainNames {
+		var nodeNames []string
+		for nodeName := range ctx.Config.Chains[chainName].Nodes {
+			nodeNames = append(nodeNames, nodeName)
+		}
+		sort.Strings(nodeNames)
+		for _, nodeName := range nodeNames {
+			fullNodename := fmt.Sprintf("%s.%s", chainName, nodeName)
+			nodes.StaticConfigs = append(nodes.StaticConfigs, prometheusStaticConfig{
+				Targets: []string{fmt.Sprintf("127.0.0.1:%d", ctx.Config.GetPrometheusPort(fullNodename))},
+				Labels: map[string]string{
+					"chain": chainName,
+					"node":  fullNodename,
+				},
+			})
+		}
+	}
+
+	hermes := prometheusScrapeConfig{JobName: "hermes"}
+	for i := range ctx.Config.Hermes {
+		hermesName := config.GetHermesName(i)
+		if !ctx.Config.GetHermesTelemetryEnabled(hermesName) {
+			continue
+		}
+		hermes.StaticConfigs = append(hermes.StaticConfigs, prometheusStaticConfig{
+			Targets: []string{fmt.Sprintf("%s:%d", ctx.Config.GetHermesTelemetryHost(hermesName), ctx.Config.GetHermesTelemetryPort(hermesName))},
+			Labels:  map[string]string{"relayer": hermesName},
+		})
+	}
+
+	relayers := prometheusScrapeConfig{JobName: "rly", MetricsPath: "/relayer/metrics"}
+	for i := range ctx.Config.Relayers {
+		relayerName := config.GetRelayerName(i)
+		relayers.StaticConfigs = append(relayers.StaticConfigs, prometheusStaticConfig{
+			Targets: []string{fmt.Sprintf("127.0.0.1:%d", ctx.Config.GetRelayerDebugPort(relayerName))},
+			Labels:  map[string]string{"relayer": relayerName},
+		})
+	}
+
+	prometheusCfg := prometheusFile{
+		Global: prometheusGlobal{ScrapeInterval: "5s"},
+	}
+	for _, scrapeConfig := range []prometheusScrapeConfig{nodes, hermes, relayers} {
+		if len(scrapeConfig.StaticConfigs) > 0 {
+			prometheusCfg.ScrapeConfigs = append(prometheusCfg.ScrapeConfigs, scrapeConfig)
+		}
+	}
+	data, err := yaml.Marshal(prometheusCfg)
+	if err != nil {
+		ux.Fatal("could not encode prometheus config: %s", err)
+	}
+	configFile := ctx.Config.GetPrometheusConfig()
+	err = os.MkdirAll(filepath.Dir(configFile), fs.ModeDir|fs.ModePerm)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		ux.Fatal("could not create prometheus config folder at %s", filepath.Dir(configFile))
+	}
+	err = ioutil.WriteFile(configFile, data, fs.ModePerm)
+	if err != nil {
+		ux.Fatal("could not write prometheus config, %s", err.Error())
+	}
+	ux.Debug("successful config creation for prometheus at %s", configFile)
+	return ux.Result{Name: "prometheus", Result: "initialized"}
+}
@@ -0,0 +1,183 @@
+package initialize
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"tm/tm/v2/config"
+	"tm/tm/v2/consts"
+	"tm/tm/v2/context"
+	"tm/tm/v2/execute"
+	"tm/tm/v2/ux"
+)
+
+// relayerGlobal defines the global section of the Go relayer configuration.
+type relayerGlobal struct {
+	APIListenAddr  string `yaml:"api-listen-addr"`
+	Timeout        string `yaml:"timeout"`
+	Memo           string `yaml:"memo"`
+	LightCacheSize uint   `yaml:"light-cache-size"`
+}
+
+type relayerChainValue struct {
+	Key            string  `yaml:"key"`
+	ChainID        string  `yaml:"chain-id"`
+	RPCAddr        string  `yaml:"rpc-addr"`
+	AccountPrefix  string  `yaml:"account-prefix"`
+	KeyringBackend string  `yaml:"keyring-backend"`
+	KeyDirectory   string  `yaml:"key-directory"`
+	GasAdjustment  float64 `yaml:"gas-adjustment"`
+	GasPrices      string  `yaml:"gas-prices"`
+	Debug          bool    `yaml:"debug"`
+	Timeout        string  `yaml:"timeout"`
+	OutputFormat   string  `yaml:"output-format"`
+	SignMode       string  `yaml:"sign-mode"`
+}
+
+// relayerChain defines one chain in the Go relayer configuration.
+type relayerChain struct {
+	Type  string            `yaml:"type"`
+	Value relayerChainValue `yaml:"value"`
+}
+
+type relayerPathEnd struct {
+	ChainID string `yaml:"chain-id"`
+}
+
+type relayerChannelFilter struct {
+	Rule        string   `yaml:"rule"`
+	ChannelList []string `yaml:"channel-list"`
+}
+
+// relayerPath defines one path between two chains in the Go relayer configuration.
+type relayerPath struct {
+	Src              relayerPathEnd       `yaml:"src"`
+	Dst              relayerPathEnd       `yaml:"dst"`
+	SrcChannelFilter relayerChannelFilter `yaml:"src-channel-filter"`
+}
+
+// relayerFile defines the Go relayer configuration file format.
+type relayerFile struct {
+	Global relayerGlobal           `yaml:"global"`
+	Chains map[string]relayerChain `yaml:"chains"`
+	Paths  map[string]relayerPath  `yaml:"paths"`
+}
+
+// createRelayerConfigs writes the configuration file of all Go relayer instances that connect to an initialized chain.
+func createRelayerConfigs(ctx context.Context, doneNetworkNames []string) []ux.Result {
+	var results []ux.Result
+	for i, relayer := range ctx.Config.Relayers {
+		relayerName := config.GetRelayerName(i)
+		if !connectsTo(relayer.Nodes, doneNetworkNames) {
+			continue
+		}
+		relayerCfg, err := newRelayerFile(ctx, relayerName)
+		if err != nil {
+			ux.Warn("%s config not created: %s", relayerName, err)
+			results = append(results, ux.Result{Name: relayerName, Result: ux.ResultFailed, Error: err.Error()})
+			continue
+		}
+		data, err := yaml.Marshal(relayerCfg)
+		if err != nil {
+			ux.Fatal("could not encode %s config: %s", relayerName, err)
+		}
+		configFile := ctx.Config.GetRelayerConfig(relayerName)
+		err = os.MkdirAll(filepath.Dir(configFile), fs.ModeDir|fs.ModePerm)
+		if err != nil && !errors.Is(err, os.ErrExist) {
+			ux.Fatal("could not create %s config folder at %s", relayerName, filepath.Dir(configFile))
+		}
+		err = ioutil.WriteFile(configFile, data, fs.ModePerm)
+		if err != nil {
+			ux.Fatal("could not write %s config, %s", relayerName, err.Error())
+		}
+		ux.Debug("successful config creation for %s at %s", relayerName, configFile)
+		addRelayerKeys(ctx, relayerName)
+		results = append(results, ux.Result{Name: relayerName, Result: "initialized"})
+	}
+	return results
+}
+
+// newRelayerFile assembles the Go relayer configuration from the tm config and the initialized chains. A path is
+// created between each pair of connected chains. Paths only have chain IDs, the clients, the connection and the channel
+// are created when the chains are connected with "tm ibc connect" or a [[channel]] definition.
+func newRelayerFile(ctx context.Context, relayerName string) (relayerFile, error) {
+	i, relayer := ctx.Config.FindRelayer(relayerName)
+	home := ctx.Config.GetRelayerHome(relayerName)
+	result := relayerFile{
+		Global: relayerGlobal{
+			APIListenAddr:  fmt.Sprintf(":%d", consts.RelayerAPIPort+i),
+			Timeout:        "10s",
+			LightCacheSize: 20,
+		},
+		Chains: make(map[string]relayerChain),
+		Paths:  make(map[string]relayerPath),
+	}
+	var chainNames []string
+	for _, fullNodename := range relayer.Nodes {
+		chainName := strings.Split(fullNodename, ".")[0]
+		if _, err := os.Stat(ctx.Config.GetChainPath(chainName, "config/genesis.json")); err != nil {
+			return result, fmt.Errorf("chain %s is not initialized", chainName)
+		}
+		accountPrefix, err := getAccountPrefix(ctx, chainName, relayerName)
+		if err != nil {
+			return result, err
+		}
+		result.Chains[chainName] = relayerChain{
+			Type: "cosmos",
+			Value: relayerChainValue{
+				Key:            relayerName,
+				ChainID:        chainName,
+				RPCAddr:        fmt.Sprintf("http://127.0.0.1:%d", ctx.Config.GetRPCPort(fullNodename)),
+				AccountPrefix:  accountPrefix,
+				KeyringBackend: "test",
+				KeyDirectory:   getRelayerKeyDir(home, chainName),
+				GasAdjustment:  1.2,
+				GasPrices:      fmt.Sprintf("0.01%s", ctx.Config.GetDenom(fullNodename)),
+				Timeout:        "20s",
+				OutputFormat:   "json",
+				SignMode:       "direct",
+			},
+		}
+		for _, chainNameLoop := range chainNames {
+			result.Paths[config.GetRelayerPathName(chainNameLoop, chainName)] = relayerPath{
+				Src:              relayerPathEnd{ChainID: chainNameLoop},
+				Dst:              relayerPathEnd{ChainID: chainName},
+				SrcChannelFilter: relayerChannelFilter{ChannelList: []string{}},
+			}
+		}
+		chainNames = append(chainNames, chainName)
+	}
+	return result, nil
+}
+
+// addRelayerKeys restores the relayer wallet of each connected chain into the Go relayer keystore.
+func addRelayerKeys(ctx context.Context, relayerName string) {
+	_, relayer := ctx.Config.FindRelayer(relayerName)
+	home := ctx.Config.GetRelayerHome(relayerName)
+	for _, fullNodename := range relayer.Nodes {
+		chainName := strings.Split(fullNodename, ".")[0]
+		mnemonics := ctx.Config.GetMnemonics(chainName, relayerName)
+		// Recovered keys do not store their mnemonics in the chain home.
+		if mnemonics == "" {
+			mnemonics = relayer.Mnemonics
+		}
+		if mnemonics == "" {
+			ux.Warn("no mnemonics found for %s on chain %s", relayerName, chainName)
+			continue
+		}
+		err := execute.RelayerKeysAdd(ctx.Config.GetBinary(fullNodename), getRelayerKeyDir(home, chainName), relayerName, ctx.Config.Chains[chainName].HDPath, mnemonics)
+		if err != nil {
+			ux.Warn("could not add key %s to %s keystore for chain %s: %s", relayerName, relayerName, chainName, err)
+		}
+	}
+}
+
+// getRelayerKeyDir returns the keyring folder of a chain in the Go relayer home.
+func getRelayerKeyDir(home string, chainName string) string {
+	return filepath.Join(home, "keys", chainName)
+}
@@ -0,0 +1,238 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Log levels, from the lowest to the highest.
+const (
+	LevelTrace = "trace"
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// levelOrder ranks the log levels.
+var levelOrder = map[string]int{
+	LevelTrace: 0,
+	LevelDebug: 1,
+	LevelInfo:  2,
+	LevelWarn:  3,
+	LevelError: 4,
+}
+
+// levelNames maps the level names used by Tendermint, Hermes and the Go relayer to the log levels.
+var levelNames = map[string]string{
+	"t": LevelTrace, "trc": LevelTrace, "trace": LevelTrace,
+	"d": LevelDebug, "dbg": LevelDebug, "debug": LevelDebug,
+	"i": LevelInfo, "inf": LevelInfo, "info": LevelInfo,
+	"w": LevelWarn, "wrn": LevelWarn, "warn": LevelWarn, "warning": LevelWarn,
+	"e": LevelError, "err": LevelError, "error": LevelError, "fatal": LevelError, "ftl": LevelError, "panic": LevelError,
+}
+
+// legacyTimeLayout is the timestamp layout of the Tendermint plain-text logs, e.g. I[2022-05-01|12:00:00.123].
+const legacyTimeLayout = "2006-01-02|15:04:05.000"
+
+// JSON log line keys.
+var (
+	jsonTimeKeys    = []string{"time", "ts", "timestamp"}
+	jsonLevelKeys   = []string{"level", "lvl"}
+	jsonMessageKeys = []string{"message", "msg", "_msg"}
+)
+
+// ansiPattern matches the ANSI color codes of colored console logs.
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// fieldPattern matches a key=value field of a plain-text log line.
+var fieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]*=`)
+
+// Entry is a parsed log line. Lines that do not match a known format only have a message.
+type Entry struct {
+	Time    time.Time
+	Level   string
+	Module  string
+	Message string
+	Fields  map[string]string
+}
+
+// Parse parses a log line in the Tendermint plain-text or JSON log format, or the Hermes or Go relayer log format.
+func Parse(line string) Entry {
+	line = strings.TrimSpace(ansiPattern.ReplaceAllString(line, ""))
+	if strings.HasPrefix(line, "{") {
+		if entry, ok := parseJSON(line); ok {
+			return entry
+		}
+	}
+	entry := Entry{Fields: make(map[string]string)}
+	rest := line
+
+	// Tendermint legacy plain-text format: I[2022-05-01|12:00:00.123] message module=state
+	if len(line) > 2 && line[1] == '[' {
+		if end := strings.Index(line, "]"); end > 2 {
+			if t, err := time.ParseInLocation(legacyTimeLayout, line[2:end], time.Local); err == nil {
+				entry.Time = t
+				entry.Level = levelNames[strings.ToLower(line[:1])]
+				rest = line[end+1:]
+			}
+		}
+	}
+
+	if entry.Time.IsZero() {
+		fields := strings.Fields(line)
+		if len(fields) > 0 {
+			if t, ok := parseTimeField(fields[0]); ok {
+				// Hermes and Go relayer format: 2022-05-01T12:00:00.123456Z INFO message
+				// Tendermint console format: 12:00PM INF message module=state
+				entry.Time = t
+				rest = strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+				if len(fields) > 1 {
+					if level, ok := levelNames[strings.ToLower(fields[1])]; ok {
+						entry.Level = level
+						rest = strings.TrimSpace(strings.TrimPrefix(rest, fields[1]))
+					}
+				}
+			}
+		}
+	}
+
+	// Go relayer format: the fields follow the message as a tab-separated JSON object.
+	if start := strings.Index(rest, "\t{\""); start >= 0 && strings.HasSuffix(rest, "}") {
+		if fields, ok := parseJSON(rest[start+1:]); ok {
+			for key, value := range fields.Fields {
+				entry.Fields[key] = value
+			}
+			rest = rest[:start]
+		}
+	}
+
+	var message []string
+	inFields := false
+	for _, token := range splitTokens(rest) {
+		if fieldPattern.MatchString(token) {
+			keyValue := strings.SplitN(token, "=", 2)
+			entry.Fields[keyValue[0]] = strings.Trim(keyValue[1], `"`)
+			inFields = true
+			continue
+		}
+		// The message ends at the first field.
+		if !inFields {
+			message = append(message, token)
+		}
+	}
+	entry.Message = strings.Join(message, " ")
+	entry.Module = entry.Fields["module"]
+	return entry
+}
+
+// splitTokens splits a plain-text log line at spaces, except inside double quotes, so a quoted field value with
+// spaces stays one token.
+func splitTokens(line string) []string {
+	var result []string
+	var token strings.Builder
+	quoted := false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			token.WriteRune(r)
+		case !quoted && (r == ' ' || r == '\t'):
+			if token.Len() > 0 {
+				result = append(result, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if token.Len() > 0 {
+		result = append(result, token.String())
+	}
+	return result
+}
+
+// parseJSON parses a JSON log line.
+func parseJSON(line string) (Entry, bool) {
+	var data map[string]interface{}
+	if json.Unmarshal([]byte(line), &data) != nil {
+		return Entry{}, false
+	}
+	entry := Entry{Fields: make(map[string]string)}
+	for _, key := range jsonTimeKeys {
+		if t, ok := parseJSONTime(data[key]); ok {
+			entry.Time = t
+			delete(data, key)
+			break
+		}
+	}
+	for _, key := range jsonLevelKeys {
+		if level, ok := data[key].(string); ok {
+			entry.Level = levelNames[strings.ToLower(level)]
+			delete(data, key)
+			break
+		}
+	}
+	for _, key := range jsonMessageKeys {
+		if message, ok := data[key].(string); ok {
+			entry.Message = message
+			delete(data, key)
+			break
+		}
+	}
+	for key, value := range data {
+		if s, ok := value.(string); ok {
+			entry.Fields[key] = s
+		} else {
+			entry.Fields[key] = fmt.Sprint(value)
+		}
+	}
+	entry.Module = entry.Fields["module"]
+	return entry, true
+}
+
+// parseTimeField parses the leading timestamp of the Hermes, Go relayer and Tendermint console formats.
+func parseTimeField(field string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, field); err == nil {
+		return t, true
+	}
+	// The console format only has the time of day.
+	if t, err := time.ParseInLocation(time.Kitchen, field, time.Local); err == nil {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.Local), true
+	}
+	return time.Time{}, false
+}
+
+// parseJSONTime parses an RFC3339 string or a UNIX timestamp in seconds.
+func parseJSONTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	case float64:
+		seconds := int64(v)
+		return time.Unix(seconds, int64((v-float64(seconds))*float64(time.Second))), true
+	}
+	return time.Time{}, false
+}
+
+// IsContinuation returns true if the entry has no timestamp, level or fields, e.g. a line of a stack trace. These lines
+// belong to the previous entry.
+func (e Entry) IsContinuation() bool {
+	return e.Time.IsZero() && e.Level == "" && len(e.Fields) == 0
+}
+
+// fieldStrings returns the fields as sorted key=value strings.
+func (e Entry) fieldStrings() []string {
+	var result []string
+	for key, value := range e.Fields {
+		result = append(result, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,127 @@
+package logs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		time    time.Time
+		level   string
+		module  string
+		message string
+		fields  map[string]string
+	}{{
+		name:    "legacy",
+		line:    `I[2022-05-01|12:00:00.123] executed block                               module=state height=42 num_valid_txs=0 num_invalid_txs=0`,
+		time:    time.Date(2022, 5, 1, 12, 0, 0, 123000000, time.Local),
+		level:   LevelInfo,
+		module:  "state",
+		message: "executed block",
+		fields:  map[string]string{"module": "state", "height": "42", "num_valid_txs": "0", "num_invalid_txs": "0"},
+	}, {
+		name:    "legacy quoted field",
+		line:    `E[2022-05-01|12:00:01.456] Stopping peer for error                      module=p2p peer="Peer{MConn{127.0.0.1:26656} 5e8e3f out}" err=EOF`,
+		time:    time.Date(2022, 5, 1, 12, 0, 1, 456000000, time.Local),
+		level:   LevelError,
+		module:  "p2p",
+		message: "Stopping peer for error",
+		fields:  map[string]string{"module": "p2p", "peer": "Peer{MConn{127.0.0.1:26656} 5e8e3f out}", "err": "EOF"},
+	}, {
+		name:    "json",
+		line:    `{"level":"info","module":"consensus","height":42,"round":0,"time":"2022-05-01T12:00:00Z","message":"Timed out"}`,
+		time:    time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC),
+		level:   LevelInfo,
+		module:  "consensus",
+		message: "Timed out",
+		fields:  map[string]string{"module": "consensus", "height": "42", "round": "0"},
+	}, {
+		name:    "json unix time",
+		line:    `{"_msg":"Committed state","lvl":"dbg","module":"state","ts":1651406400}`,
+		time:    time.Unix(1651406400, 0),
+		level:   LevelDebug,
+		module:  "state",
+		message: "Committed state",
+		fields:  map[string]string{"module": "state"},
+	}, {
+		name:    "console",
+		line:    "\x1b[90m12:00PM\x1b[0m \x1b[32mINF\x1b[0m executed block \x1b[36mheight=\x1b[0m42 \x1b[36mmodule=\x1b[0mstate \x1b[36mnum_invalid_txs=\x1b[0m0",
+		level:   LevelInfo,
+		module:  "state",
+		message: "executed block",
+		fields:  map[string]string{"height": "42", "module": "state", "num_invalid_txs": "0"},
+	}, {
+		name:    "hermes",
+		line:    `2022-05-01T12:00:00.123456Z  WARN ThreadId(22) [ibc-0 -> ibc-1] packet relay failed`,
+		time:    time.Date(2022, 5, 1, 12, 0, 0, 123456000, time.UTC),
+		level:   LevelWarn,
+		message: "ThreadId(22) [ibc-0 -> ibc-1] packet relay failed",
+		fields:  map[string]string{},
+	}, {
+		name:    "rly",
+		line:    "2022-05-01T12:00:00.123456Z\tinfo\tSuccessful transaction\t{\"provider_type\": \"cosmos\", \"chain_id\": \"ibc-0\", \"gas_used\": 95000}",
+		time:    time.Date(2022, 5, 1, 12, 0, 0, 123456000, time.UTC),
+		level:   LevelInfo,
+		message: "Successful transaction",
+		fields:  map[string]string{"provider_type": "cosmos", "chain_id": "ibc-0", "gas_used": "95000"},
+	}, {
+		name:    "unknown",
+		line:    "panic: runtime error: invalid memory address or nil pointer dereference",
+		message: "panic: runtime error: invalid memory address or nil pointer dereference",
+		fields:  map[string]string{},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entry := Parse(test.line)
+			if !test.time.IsZero() && !entry.Time.Equal(test.time) {
+				t.Errorf("time %s, expected %s", entry.Time, test.time)
+			}
+			if entry.Level != test.level {
+				t.Errorf("level %q, expected %q", entry.Level, test.level)
+			}
+			if entry.Module != test.module {
+				t.Errorf("module %q, expected %q", entry.Module, test.module)
+			}
+			if entry.Message != test.message {
+				t.Errorf("message %q, expected %q", entry.Message, test.message)
+			}
+			if !reflect.DeepEqual(entry.Fields, test.fields) {
+				t.Errorf("fields %v, expected %v", entry.Fields, test.fields)
+			}
+		})
+	}
+}
+
+func TestParseConsoleTime(t *testing.T) {
+	entry := Parse("3:04PM ERR CONSENSUS FAILURE!!! module=consensus")
+	if entry.Time.Hour() != 15 || entry.Time.Minute() != 4 {
+		t.Errorf("time %s, expected 15:04", entry.Time)
+	}
+	if entry.Level != LevelError || entry.Message != "CONSENSUS FAILURE!!!" {
+		t.Errorf("level %q and message %q", entry.Level, entry.Message)
+	}
+}
+
+func TestIsContinuation(t *testing.T) {
+	tests := []struct {
+		line         string
+		continuation bool
+	}{
+		{"goroutine 1 [running]:", true},
+		{"\t/go/src/github.com/tendermint/tendermint/consensus/state.go:1234 +0x1d", true},
+		{"panic: runtime error: index out of range [3] with length 3", true},
+		{"I[2022-05-01|12:00:00.123] executed block module=state", false},
+		{`{"level":"error","message":"failed"}`, false},
+		{"2022-05-01T12:00:00Z ERROR ThreadId(01) failed", false},
+		{"failed to connect err=EOF", false},
+	}
+	for _, test := range tests {
+		if continuation := Parse(test.line).IsContinuation(); continuation != test.continuation {
+			t.Errorf("%q continuation %t, expected %t", test.line, continuation, test.continuation)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package logs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"tm/tm/v2/utils"
+)
+
+// sinceLayouts are the accepted time formats of the since filter, besides durations.
+var sinceLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// Filter selects log entries by level, module, message and time. Empty criteria match all entries.
+type Filter struct {
+	// Level is the lowest level shown.
+	Level string
+	// Modules are the modules shown.
+	Modules []string
+	// Grep matches the message or the key=value fields.
+	Grep *regexp.Regexp
+	// Since is the earliest time shown.
+	Since time.Time
+}
+
+// NewFilter creates a filter from the command-line values. Modules are comma-separated. Since is a duration before now
+// (e.g. 10m) or a time.
+func NewFilter(level string, modules string, grep string, since string) (Filter, error) {
+	var result Filter
+	if level != "" {
+		var ok bool
+		result.Level, ok = levelNames[strings.ToLower(level)]
+		if !ok {
+			return result, fmt.Errorf("invalid level %s", level)
+		}
+	}
+	for _, module := range strings.Split(modules, ",") {
+		if module = strings.TrimSpace(module); module != "" {
+			result.Modules = append(result.Modules, module)
+		}
+	}
+	if grep != "" {
+		var err error
+		result.Grep, err = regexp.Compile(grep)
+		if err != nil {
+			return result, fmt.Errorf("invalid regular expression %s: %s", grep, err)
+		}
+	}
+	if since != "" {
+		var err error
+		result.Since, err = parseSince(since)
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// parseSince parses a duration before now or a time in the local time zone.
+func parseSince(since string) (time.Time, error) {
+	if duration, err := time.ParseDuration(since); err == nil {
+		return time.Now().Add(-duration), nil
+	}
+	for _, layout := range sinceLayouts {
+		if t, err := time.ParseInLocation(layout, since, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %s", since)
+}
+
+// IsEmpty returns true if the filter matches all entries.
+func (f Filter) IsEmpty() bool {
+	return f.Level == "" && len(f.Modules) == 0 && f.Grep == nil && f.Since.IsZero()
+}
+
+// Match returns true if the entry passes all criteria of the filter. Entries without a level or time pass the level or
+// time criteria.
+func (f Filter) Match(entry Entry) bool {
+	if f.Level != "" && entry.Level != "" && levelOrder[entry.Level] < levelOrder[f.Level] {
+		return false
+	}
+	if len(f.Modules) > 0 && !utils.Contains(f.Modules, entry.Module) {
+		return false
+	}
+	if f.Grep != nil && !f.Grep.MatchString(entry.Message) {
+		found := false
+		for _, field := range entry.fieldStrings() {
+			if f.Grep.MatchString(field) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && !entry.Time.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// Matcher applies a filter to the consecutive lines of a log. Continuation lines are shown if the entry they belong
+// to is shown.
+type Matcher struct {
+	filter  Filter
+	matched bool
+}
+
+// NewMatcher creates a matcher for a log. Continuation lines at the start of the log are only shown by an empty filter.
+func NewMatcher(filter Filter) *Matcher {
+	return &Matcher{filter: filter, matched: filter.IsEmpty()}
+}
+
+// Match returns true if the line of the entry is shown.
+func (m *Matcher) Match(entry Entry) bool {
+	if !entry.IsContinuation() {
+		m.matched = m.filter.Match(entry)
+	}
+	return m.matched
+}
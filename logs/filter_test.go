@@ -0,0 +1,128 @@
+package logs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		modules string
+		grep    string
+		since   string
+		valid   bool
+	}{
+		{name: "empty", valid: true},
+		{name: "level alias", level: "WRN", valid: true},
+		{name: "invalid level", level: "loud"},
+		{name: "modules", modules: "state, p2p,", valid: true},
+		{name: "grep", grep: "height=4[0-9]", valid: true},
+		{name: "invalid grep", grep: "height=("},
+		{name: "since duration", since: "10m", valid: true},
+		{name: "since date", since: "2022-05-01", valid: true},
+		{name: "since time", since: "2022-05-01 12:00:00", valid: true},
+		{name: "invalid since", since: "yesterday"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewFilter(test.level, test.modules, test.grep, test.since)
+			if test.valid && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if !test.valid && err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	lines := []string{
+		`I[2022-05-01|12:00:00.123] executed block                               module=state height=42 num_valid_txs=0`,
+		`E[2022-05-01|12:00:01.456] Stopping peer for error                      module=p2p peer="Peer{MConn{127.0.0.1:26656} 5e8e3f out}" err=EOF`,
+		`D[2022-05-01|11:59:00.000] Receive                                      module=p2p chID=32`,
+		`{"level":"warn","module":"consensus","height":43,"time":"2099-01-01T00:00:00Z","message":"Timed out"}`,
+		"2099-01-01T00:00:00Z\terror\tFailed to send messages\t{\"chain_id\": \"ibc-0\"}",
+	}
+	tests := []struct {
+		name    string
+		level   string
+		modules string
+		grep    string
+		since   string
+		matched []bool
+	}{
+		{name: "empty", matched: []bool{true, true, true, true, true}},
+		{name: "level", level: "warn", matched: []bool{false, true, false, true, true}},
+		{name: "module", modules: "p2p,consensus", matched: []bool{false, true, true, true, false}},
+		{name: "grep message", grep: "(?i)timed out|failed", matched: []bool{false, false, false, true, true}},
+		{name: "grep fields", grep: "^height=4[23]$", matched: []bool{true, false, false, true, false}},
+		{name: "since", since: "2022-05-01 12:00:00", matched: []bool{true, true, false, true, true}},
+		{name: "combined", level: "info", modules: "p2p", grep: "EOF", matched: []bool{false, true, false, false, false}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := NewFilter(test.level, test.modules, test.grep, test.since)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, line := range lines {
+				if matched := filter.Match(Parse(line)); matched != test.matched[i] {
+					t.Errorf("line %d matched %t, expected %t", i, matched, test.matched[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilterSinceDuration(t *testing.T) {
+	filter, err := NewFilter("", "", "", "1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.Match(Entry{Time: time.Now().Add(-2 * time.Hour)}) {
+		t.Errorf("entry before the duration matched")
+	}
+	if !filter.Match(Entry{Time: time.Now().Add(-time.Minute)}) {
+		t.Errorf("entry within the duration not matched")
+	}
+	if !filter.Match(Entry{Message: "no timestamp"}) {
+		t.Errorf("entry without a timestamp not matched")
+	}
+}
+
+func TestMatcherContinuation(t *testing.T) {
+	lines := []string{
+		"goroutine 7 [running]:",
+		`I[2022-05-01|12:00:00.123] executed block                               module=state height=42`,
+		"\t/go/src/github.com/tendermint/tendermint/state/execution.go:130 +0x1d",
+		`E[2022-05-01|12:00:01.456] CONSENSUS FAILURE!!!                         module=consensus err="index out of range"`,
+		"goroutine 1 [running]:",
+		"\t/go/src/github.com/tendermint/tendermint/consensus/state.go:726 +0x2c5",
+	}
+	tests := []struct {
+		name    string
+		level   string
+		matched []bool
+	}{
+		{name: "empty", matched: []bool{true, true, true, true, true, true}},
+		{name: "error", level: "error", matched: []bool{false, false, false, true, true, true}},
+		{name: "info", level: "info", matched: []bool{false, true, true, true, true, true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := NewFilter(test.level, "", "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			matcher := NewMatcher(filter)
+			for i, line := range lines {
+				if matched := matcher.Match(Parse(line)); matched != test.matched[i] {
+					t.Errorf("line %d matched %t, expected %t", i, matched, test.matched[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Timeout is the maximum time a single RPC query can take.
+const Timeout = 2 * time.Second
+
+// response is the JSON-RPC envelope of the Tendermint RPC responses.
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// SyncInfo is the sync_info section of the /status response.
+type SyncInfo struct {
+	LatestBlockHash   string    `json:"latest_block_hash"`
+	LatestAppHash     string    `json:"latest_app_hash"`
+	LatestBlockHeight uint64    `json:"latest_block_height,string"`
+	LatestBlockTime   time.Time `json:"latest_block_time"`
+	CatchingUp        bool      `json:"catching_up"`
+}
+
+// ValidatorInfo is the validator_info section of the /status response.
+type ValidatorInfo struct {
+	Address     string `json:"address"`
+	VotingPower int64  `json:"voting_power,string"`
+}
+
+// NodeInfo is the node_info section of the /status response.
+type NodeInfo struct {
+	ID      string `json:"id"`
+	Network string `json:"network"`
+	Version string `json:"version"`
+	Moniker string `json:"moniker"`
+}
+
+// Status is the /status response.
+type Status struct {
+	NodeInfo      NodeInfo      `json:"node_info"`
+	SyncInfo      SyncInfo      `json:"sync_info"`
+	ValidatorInfo ValidatorInfo `json:"validator_info"`
+}
+
+// query calls an RPC endpoint of the node listening on the local port and decodes the result.
+func query(port uint, endpoint string, result interface{}) error {
+	client := http.Client{Timeout: Timeout}
+	resp, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d/%s", port, endpoint))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var data response
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal %s response: %s", endpoint, err)
+	}
+	if data.Error != nil {
+		return fmt.Errorf("%s: %s %s", endpoint, data.Error.Message, data.Error.Data)
+	}
+	err = json.Unmarshal(data.Result, result)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal %s result: %s", endpoint, err)
+	}
+	return nil
+}
+
+// GetStatus returns the /status of the node listening on the local port.
+func GetStatus(port uint) (*Status, error) {
+	var result Status
+	err := query(port, "status", &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Peer is one peer in the /net_info response.
+type Peer struct {
+	NodeInfo   NodeInfo `json:"node_info"`
+	IsOutbound bool     `json:"is_outbound"`
+	RemoteIP   string   `json:"remote_ip"`
+}
+
+// NetInfo is the /net_info response.
+type NetInfo struct {
+	Listening bool   `json:"listening"`
+	NPeers    uint   `json:"n_peers,string"`
+	Peers     []Peer `json:"peers"`
+}
+
+// CommitSignature is one signature in the last commit of a block.
+type CommitSignature struct {
+	BlockIDFlag      int    `json:"block_id_flag"`
+	ValidatorAddress string `json:"validator_address"`
+}
+
+// Commit is the /commit response.
+type Commit struct {
+	SignedHeader struct {
+		Header struct {
+			Height  uint64 `json:"height,string"`
+			AppHash string `json:"app_hash"`
+		} `json:"header"`
+		Commit struct {
+			Height     uint64            `json:"height,string"`
+			Signatures []CommitSignature `json:"signatures"`
+		} `json:"commit"`
+	} `json:"signed_header"`
+}
+
+// GetNetInfo returns the /net_info of the node listening on the local port.
+func GetNetInfo(port uint) (*NetInfo, error) {
+	var result NetInfo
+	err := query(port, "net_info", &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Signed returns true if the validator address signed the commit.
+func (c Commit) Signed(validatorAddress string) bool {
+	for _, signature := range c.SignedHeader.Commit.Signatures {
+		// BlockIDFlag 2 is a commit signature, 1 is absent and 3 is a nil vote.
+		if signature.ValidatorAddress == validatorAddress && signature.BlockIDFlag == 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// UnconfirmedTxs is the /num_unconfirmed_txs response.
+type UnconfirmedTxs struct {
+	Count      uint64 `json:"n_txs,string"`
+	Total      uint64 `json:"total,string"`
+	TotalBytes uint64 `json:"total_bytes,string"`
+}
+
+// GetUnconfirmedTxs returns the mempool size of the node listening on the local port.
+func GetUnconfirmedTxs(port uint) (*UnconfirmedTxs, error) {
+	var result UnconfirmedTxs
+	err := query(port, "num_unconfirmed_txs", &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RoundStepNames are the names of the Tendermint consensus round steps.
+var RoundStepNames = map[int]string{
+	1: "NewHeight",
+	2: "NewRound",
+	3: "Propose",
+	4: "Prevote",
+	5: "PrevoteWait",
+	6: "Precommit",
+	7: "PrecommitWait",
+	8: "Commit",
+}
+
+// Validator is one validator of the validator set in the consensus state.
+type Validator struct {
+	Address     string `json:"address"`
+	VotingPower int64  `json:"voting_power,string"`
+}
+
+// HeightVoteSet are the votes of one round in the consensus state. Missing votes are "nil-Vote".
+type HeightVoteSet struct {
+	Round      int      `json:"round"`
+	Prevotes   []string `json:"prevotes"`
+	Precommits []string `json:"precommits"`
+}
+
+// RoundState is the round_state section of the /dump_consensus_state response.
+type RoundState struct {
+	Height     uint64 `json:"height,string"`
+	Round      int    `json:"round"`
+	Step       int    `json:"step"`
+	Validators struct {
+		Validators []Validator `json:"validators"`
+	} `json:"validators"`
+	Votes []HeightVoteSet `json:"votes"`
+}
+
+// ConsensusState is the /dump_consensus_state response.
+type ConsensusState struct {
+	RoundState RoundState `json:"round_state"`
+}
+
+// GetConsensusState returns the /dump_consensus_state of the node listening on the local port.
+func GetConsensusState(port uint) (*ConsensusState, error) {
+	var result ConsensusState
+	err := query(port, "dump_consensus_state", &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetCommitAt returns the /commit of a block of the node listening on the local port.
+func GetCommitAt(port uint, height uint64) (*Commit, error) {
+	var result Commit
+	err := query(port, fmt.Sprintf("commit?height=%d", height), &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// StepName returns the name of the consensus round step.
+func (r RoundState) StepName() string {
+	if name, ok := RoundStepNames[r.Step]; ok {
+		return name
+	}
+	return fmt.Sprintf("step %d", r.Step)
+}
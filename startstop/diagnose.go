@@ -0,0 +1,330 @@
+package startstop
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"tm/tm/v2/consts"
+	"tm/tm/v2/context"
+	"tm/tm/v2/rpc"
+	"tm/tm/v2/ux"
+)
+
+// nilVote is the consensus state entry of a validator whose vote was not received.
+const nilVote = "nil-Vote"
+
+// maxLogFindingLength is the length at which log lines are cut in the diagnosis.
+const maxLogFindingLength = 200
+
+// logFindingPatterns match the log messages that explain a chain halt.
+var logFindingPatterns = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"consensus failure", regexp.MustCompile(`CONSENSUS FAILURE`)},
+	{"app hash mismatch", regexp.MustCompile(`(?i)app ?hash mismatch|wrong Block\.Header\.AppHash`)},
+	{"panic", regexp.MustCompile(`(?i)^panic:|\bpanic\b.*:`)},
+}
+
+// voteTally is the voting power of the received prevotes or precommits of a round.
+type voteTally struct {
+	Power   int64    `json:"power"`
+	Total   int64    `json:"total"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+// nodeDiagnosis is the consensus state of a node and the problems found in its log.
+type nodeDiagnosis struct {
+	Name          string     `json:"name"`
+	State         string     `json:"state"`
+	Height        uint64     `json:"height,omitempty"`
+	Round         int        `json:"round"`
+	Step          string     `json:"step,omitempty"`
+	Prevotes      *voteTally `json:"prevotes,omitempty"`
+	Precommits    *voteTally `json:"precommits,omitempty"`
+	AppHash       string     `json:"app_hash,omitempty"`
+	AppHashHeight uint64     `json:"app_hash_height,omitempty"`
+	LogFindings   []string   `json:"log_findings,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// chainDiagnosis is the diagnosis of the nodes of a chain.
+type chainDiagnosis struct {
+	Name     string          `json:"name"`
+	Nodes    []nodeDiagnosis `json:"nodes"`
+	Problems []string        `json:"problems,omitempty"`
+}
+
+// Diagnose inspects the consensus state and the logs of the nodes in the input to explain why a chain stalls. It
+// reports where each node is stuck, which validators are missing votes and whether the nodes disagree on the app
+// hash. It returns false if any problem was found.
+func Diagnose(ctx context.Context) bool {
+	chainNodes := make(map[string][]string)
+	var chainNames []string
+	for _, fullNodename := range ctx.Input {
+		chainName := strings.Split(fullNodename, ".")[0]
+		if _, ok := chainNodes[chainName]; !ok {
+			chainNames = append(chainNames, chainName)
+		}
+		chainNodes[chainName] = append(chainNodes[chainName], fullNodename)
+	}
+	sort.Strings(chainNames)
+
+	healthy := true
+	var result []chainDiagnosis
+	for _, chainName := range chainNames {
+		sort.Strings(chainNodes[chainName])
+		diagnosis := diagnoseChain(ctx, chainName, chainNodes[chainName])
+		printChainDiagnosis(diagnosis)
+		if len(diagnosis.Problems) > 0 {
+			healthy = false
+		}
+		result = append(result, diagnosis)
+	}
+	ux.JSON(result)
+	return healthy
+}
+
+// diagnoseChain collects the consensus state of the nodes of a chain and compares them.
+func diagnoseChain(ctx context.Context, chainName string, fullNodenames []string) chainDiagnosis {
+	result := chainDiagnosis{Name: chainName}
+
+	// Validator addresses are resolved to node names.
+	statuses := make(map[string]nodeStatus)
+	validatorNames := make(map[string]string)
+	for _, fullNodename := range fullNodenames {
+		status := getNodeStatus(ctx, fullNodename)
+		statuses[fullNodename] = status
+		if status.status != nil && status.validator {
+			validatorNames[status.status.ValidatorInfo.Address] = fullNodename
+		}
+	}
+
+	for _, fullNodename := range fullNodenames {
+		status := statuses[fullNodename]
+		node := nodeDiagnosis{
+			Name:        fullNodename,
+			State:       status.state(),
+			LogFindings: scanLog(consts.GetLog(ctx.Config.GetHome(fullNodename))),
+		}
+		for _, finding := range node.LogFindings {
+			result.Problems = append(result.Problems, fmt.Sprintf("%s log has %s", fullNodename, finding))
+		}
+		switch {
+		case status.pidErr != nil:
+			node.Error = status.pidErr.Error()
+			result.Problems = append(result.Problems, fmt.Sprintf("%s has a %s", fullNodename, status.pidErr))
+		case status.pid == nil:
+			if status.validator {
+				result.Problems = append(result.Problems, fmt.Sprintf("validator %s is not running", fullNodename))
+			}
+		case status.status == nil:
+			node.Error = status.rpcErr.Error()
+			result.Problems = append(result.Problems, fmt.Sprintf("%s RPC query failed: %s", fullNodename, status.rpcErr))
+		default:
+			node.AppHash = status.status.SyncInfo.LatestAppHash
+			node.AppHashHeight = status.status.SyncInfo.LatestBlockHeight
+			consensusState, err := rpc.GetConsensusState(ctx.Config.GetRPCPort(fullNodename))
+			if err != nil {
+				node.Error = err.Error()
+				result.Problems = append(result.Problems, fmt.Sprintf("%s consensus state query failed: %s", fullNodename, err))
+				break
+			}
+			stuck := time.Since(status.status.SyncInfo.LatestBlockTime) > consts.HealthBlockWindow
+			result.Problems = append(result.Problems, diagnoseRoundState(&node, consensusState.RoundState, stuck, validatorNames)...)
+		}
+		result.Nodes = append(result.Nodes, node)
+	}
+
+	result.Problems = append(result.Problems, compareAppHashes(ctx, result.Nodes)...)
+	return result
+}
+
+// compareAppHashes compares the app hashes the nodes computed themselves after executing their latest block. The
+// header of a block carries the app hash of the previous block the proposer agreed on, so it is the same on every node
+// and cannot be used. Nodes at the same height are compared with each other, and nodes behind the highest node are
+// also compared with the app hash committed in the next block, which the chain agreed on.
+func compareAppHashes(ctx context.Context, nodes []nodeDiagnosis) []string {
+	var highest *nodeDiagnosis
+	appHashes := make(map[uint64]map[string][]string)
+	for i, node := range nodes {
+		if node.AppHashHeight == 0 {
+			continue
+		}
+		if highest == nil || node.AppHashHeight > highest.AppHashHeight {
+			highest = &nodes[i]
+		}
+		if appHashes[node.AppHashHeight] == nil {
+			appHashes[node.AppHashHeight] = make(map[string][]string)
+		}
+		appHashes[node.AppHashHeight][node.AppHash] = append(appHashes[node.AppHashHeight][node.AppHash], node.Name)
+	}
+
+	var heights []uint64
+	for height := range appHashes {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	var problems []string
+	for _, height := range heights {
+		if height < highest.AppHashHeight {
+			commit, err := rpc.GetCommitAt(ctx.Config.GetRPCPort(highest.Name), height+1)
+			if err != nil {
+				ux.Debug("commit query of %s at height %d failed: %s", highest.Name, height+1, err)
+			} else {
+				appHash := commit.SignedHeader.Header.AppHash
+				appHashes[height][appHash] = append(appHashes[height][appHash], fmt.Sprintf("block %d", height+1))
+			}
+		}
+		if len(appHashes[height]) < 2 {
+			continue
+		}
+		var groups []string
+		for appHash, names := range appHashes[height] {
+			groups = append(groups, fmt.Sprintf("%s on %s", appHash, strings.Join(names, ", ")))
+		}
+		sort.Strings(groups)
+		problems = append(problems, fmt.Sprintf("nodes disagree on the app hash at height %d: %s", height, strings.Join(groups, "; ")))
+	}
+	return problems
+}
+
+// diagnoseRoundState fills in the height, round, step and votes of the node and returns the consensus problems.
+// Prevotes are only expected from the prevote step and precommits from the precommit step. Votes arrive while a round is
+// in progress, so missing votes are only reported if the node is stuck.
+func diagnoseRoundState(node *nodeDiagnosis, roundState rpc.RoundState, stuck bool, validatorNames map[string]string) []string {
+	var problems []string
+	node.Height = roundState.Height
+	node.Round = roundState.Round
+	node.Step = roundState.StepName()
+	if roundState.Round > 0 {
+		problems = append(problems, fmt.Sprintf("%s is at round %d of height %d, earlier rounds did not commit", node.Name, roundState.Round, roundState.Height))
+	}
+	validators := roundState.Validators.Validators
+	for _, votes := range roundState.Votes {
+		if votes.Round != roundState.Round {
+			continue
+		}
+		if roundState.Step >= 4 {
+			node.Prevotes = tallyVotes(votes.Prevotes, validators, validatorNames)
+			if stuck {
+				problems = append(problems, checkTally(node.Name, "prevotes", node.Prevotes)...)
+			}
+		}
+		if roundState.Step >= 6 {
+			node.Precommits = tallyVotes(votes.Precommits, validators, validatorNames)
+			if stuck {
+				problems = append(problems, checkTally(node.Name, "precommits", node.Precommits)...)
+			}
+		}
+	}
+	return problems
+}
+
+// tallyVotes sums the voting power of the received votes and lists the validators whose vote is missing.
+func tallyVotes(votes []string, validators []rpc.Validator, validatorNames map[string]string) *voteTally {
+	result := &voteTally{}
+	for i, validator := range validators {
+		result.Total += validator.VotingPower
+		if i < len(votes) && votes[i] != nilVote {
+			result.Power += validator.VotingPower
+			continue
+		}
+		name, ok := validatorNames[validator.Address]
+		if !ok {
+			name = validator.Address
+		}
+		result.Missing = append(result.Missing, name)
+	}
+	return result
+}
+
+// checkTally returns the problems of a vote tally: missing votes and less than 2/3 of the voting power.
+func checkTally(fullNodename string, kind string, tally *voteTally) []string {
+	var problems []string
+	if len(tally.Missing) > 0 {
+		problems = append(problems, fmt.Sprintf("%s is missing %s from %s", fullNodename, kind, strings.Join(tally.Missing, ", ")))
+	}
+	if tally.Total > 0 && tally.Power*3 <= tally.Total*2 {
+		problems = append(problems, fmt.Sprintf("%s has %s of %d/%d voting power, 2/3 is required", fullNodename, kind, tally.Power, tally.Total))
+	}
+	return problems
+}
+
+// scanLog returns the last line of each kind of log message that explains a chain halt.
+func scanLog(logFile string) []string {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	lastLines := make([]string, len(logFindingPatterns))
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for i, finding := range logFindingPatterns {
+			if finding.pattern.MatchString(line) {
+				lastLines[i] = line
+			}
+		}
+	}
+	var result []string
+	for i, line := range lastLines {
+		if line == "" {
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if len(line) > maxLogFindingLength {
+			line = line[:maxLogFindingLength] + "..."
+		}
+		result = append(result, fmt.Sprintf("%s: %s", logFindingPatterns[i].name, line))
+	}
+	return result
+}
+
+// printChainDiagnosis prints the consensus state table of the nodes of a chain, followed by the problems found.
+func printChainDiagnosis(diagnosis chainDiagnosis) {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NODE\tSTATE\tHEIGHT\tROUND\tSTEP\tPREVOTES\tPRECOMMITS\tAPP HASH (HEIGHT)")
+	for _, node := range diagnosis.Nodes {
+		nodeName := strings.Split(node.Name, ".")[1]
+		height, round, step, prevotes, precommits, appHash := "-", "-", "-", "-", "-", "-"
+		if node.Step != "" {
+			height = strconv.FormatUint(node.Height, 10)
+			round = strconv.Itoa(node.Round)
+			step = node.Step
+		}
+		if node.Prevotes != nil {
+			prevotes = fmt.Sprintf("%d/%d", node.Prevotes.Power, node.Prevotes.Total)
+		}
+		if node.Precommits != nil {
+			precommits = fmt.Sprintf("%d/%d", node.Precommits.Power, node.Precommits.Total)
+		}
+		if node.AppHashHeight > 0 {
+			appHash = fmt.Sprintf("%s (%d)", node.AppHash, node.AppHashHeight)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", nodeName, node.State, height, round, step, prevotes, precommits, appHash)
+	}
+	_ = w.Flush()
+
+	ux.Info("%s", diagnosis.Name)
+	ux.Info("%s", strings.TrimSuffix(buf.String(), "\n"))
+	for _, problem := range diagnosis.Problems {
+		ux.Info("⚠ %s.", problem)
+	}
+	if len(diagnosis.Problems) == 0 {
+		ux.Info("✔ no problems found.")
+	}
+	ux.Info("")
+}
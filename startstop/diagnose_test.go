@@ -0,0 +1,31 @@
+package startstop
+
+import (
+	"testing"
+	"tm/tm/v2/rpc"
+)
+
+func TestDiagnoseRoundState(t *testing.T) {
+	roundState := rpc.RoundState{Height: 10, Step: 6}
+	roundState.Validators.Validators = []rpc.Validator{{Address: "A", VotingPower: 1}, {Address: "B", VotingPower: 1}}
+	roundState.Votes = []rpc.HeightVoteSet{{
+		Prevotes:   []string{"Vote{0:A}", nilVote},
+		Precommits: []string{"Vote{0:A}", nilVote},
+	}}
+	validatorNames := map[string]string{"A": "test.validator1", "B": "test.validator2"}
+
+	// A round in progress is waiting for votes.
+	node := nodeDiagnosis{Name: "test.validator1"}
+	if problems := diagnoseRoundState(&node, roundState, false, validatorNames); len(problems) != 0 {
+		t.Errorf("problems %v in a round in progress", problems)
+	}
+	if node.Prevotes == nil || node.Prevotes.Power != 1 || node.Precommits == nil || len(node.Precommits.Missing) != 1 {
+		t.Errorf("votes not tallied, prevotes %+v, precommits %+v", node.Prevotes, node.Precommits)
+	}
+
+	// A stuck node reports the missing votes and the missing voting power.
+	node = nodeDiagnosis{Name: "test.validator1"}
+	if problems := diagnoseRoundState(&node, roundState, true, validatorNames); len(problems) != 4 {
+		t.Errorf("problems %v, expected missing prevotes and precommits", problems)
+	}
+}
@@ -0,0 +1,114 @@
+package startstop
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"os"
+	"time"
+	"tm/tm/v2/consts"
+	"tm/tm/v2/context"
+	"tm/tm/v2/execute"
+	"tm/tm/v2/ux"
+)
+
+// Health checks that the nodes in the input are running, reachable over RPC, not catching up and have produced a block
+// within the block window, and that the relayer instances are running and have written to their log within the relayer
+// window. The --relayer-window flag overrides the relayer_window setting, and zero skips the relayer activity check.
+// With --wait, the checks are repeated until all pass or the timeout expires.
+func Health(ctx context.Context) []ux.Result {
+	blockWindow := viper.GetDuration("block-window")
+	relayerWindow := ctx.Config.GetRelayerWindow()
+	if viper.IsSet("relayer-window") {
+		relayerWindow = viper.GetDuration("relayer-window")
+	}
+	results := checkHealth(ctx, blockWindow, relayerWindow)
+	if viper.GetBool("wait") {
+		deadline := time.Now().Add(viper.GetDuration("health-timeout"))
+		for !isHealthy(results) && time.Now().Before(deadline) {
+			ux.Debug("network is not healthy yet, retrying")
+			time.Sleep(consts.HealthPollInterval)
+			results = checkHealth(ctx, blockWindow, relayerWindow)
+		}
+	}
+	for _, result := range results {
+		if result.Failed() {
+			ux.Info("✘ %s unhealthy, %s.", result.Name, result.Error)
+		} else {
+			ux.Info("✔ %s healthy.", result.Name)
+		}
+	}
+	return results
+}
+
+// isHealthy returns true if all checks passed.
+func isHealthy(results []ux.Result) bool {
+	for _, result := range results {
+		if result.Failed() {
+			return false
+		}
+	}
+	return true
+}
+
+// checkHealth runs the health checks once.
+func checkHealth(ctx context.Context, blockWindow time.Duration, relayerWindow time.Duration) []ux.Result {
+	var results []ux.Result
+	for _, fullNodename := range ctx.Input {
+		results = append(results, checkNodeHealth(ctx, fullNodename, blockWindow))
+	}
+	for _, relayer := range getRelayers(ctx) {
+		results = append(results, checkRelayerHealth(relayer, relayerWindow))
+	}
+	return results
+}
+
+// checkNodeHealth checks that a node is running, reachable, not catching up and produces blocks.
+func checkNodeHealth(ctx context.Context, fullNodename string, blockWindow time.Duration) ux.Result {
+	status := getNodeStatus(ctx, fullNodename)
+	unhealthy := func(format string, a ...any) ux.Result {
+		result := ux.Result{Name: fullNodename, Result: ux.ResultUnhealthy, Error: fmt.Sprintf(format, a...)}
+		if status.pid != nil {
+			result.Pid = *status.pid
+		}
+		return result
+	}
+	switch {
+	case status.pidErr != nil:
+		return unhealthy("%s", status.pidErr)
+	case status.pid == nil:
+		return unhealthy("not running")
+	case status.status == nil:
+		return unhealthy("RPC query failed: %s", status.rpcErr)
+	case status.status.SyncInfo.CatchingUp:
+		return unhealthy("catching up at height %d", status.status.SyncInfo.LatestBlockHeight)
+	}
+	age := time.Since(status.status.SyncInfo.LatestBlockTime)
+	if age > blockWindow {
+		return unhealthy("last block %d is %s old", status.status.SyncInfo.LatestBlockHeight, age.Round(time.Second))
+	}
+	return ux.Result{Name: fullNodename, Result: "healthy", Pid: *status.pid}
+}
+
+// checkRelayerHealth checks that a relayer instance is running and active. Relayer activity is measured by the last
+// write to its log, unless the relayer window is zero.
+func checkRelayerHealth(relayer relayer, relayerWindow time.Duration) ux.Result {
+	pid, err := execute.CheckPid(relayer.home)
+	if err != nil {
+		return ux.Result{Name: relayer.name, Result: ux.ResultUnhealthy, Error: err.Error()}
+	}
+	if pid == nil {
+		return ux.Result{Name: relayer.name, Result: ux.ResultUnhealthy, Error: "not running"}
+	}
+	if relayerWindow == 0 {
+		return ux.Result{Name: relayer.name, Result: "healthy", Pid: *pid}
+	}
+	info, err := os.Stat(consts.GetLog(relayer.home))
+	if err != nil {
+		return ux.Result{Name: relayer.name, Result: ux.ResultUnhealthy, Pid: *pid, Error: fmt.Sprintf("log not found: %s", err)}
+	}
+	idle := time.Since(info.ModTime())
+	if idle > relayerWindow {
+		return ux.Result{Name: relayer.name, Result: ux.ResultUnhealthy, Pid: *pid, Error: fmt.Sprintf("no activity for %s", idle.Round(time.Second))}
+	}
+	return ux.Result{Name: relayer.name, Result: "healthy", Pid: *pid}
+}
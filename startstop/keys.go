@@ -9,7 +9,18 @@ import (
 	"tm/tm/v2/ux"
 )
 
+// keyResult is the machine-readable output of a key in a chain keyring.
+type keyResult struct {
+	Chain    string `json:"chain"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Address  string `json:"address"`
+	PubKey   string `json:"pubkey"`
+	Mnemonic string `json:"mnemonic,omitempty"`
+}
+
 func Keys(ctx context.Context) {
+	results := make([]keyResult, 0)
 	var doneNetworkNames []string
 	for _, fullNodename := range ctx.Input {
 		fullNodenameSplit := strings.Split(fullNodename, ".")
@@ -28,6 +39,14 @@ func Keys(ctx context.Context) {
 			ux.Warn("Output string: %s", result)
 		}
 		for _, d := range data {
+			results = append(results, keyResult{
+				Chain:    chainName,
+				Name:     d.Name,
+				Type:     d.KeyType,
+				Address:  d.Address,
+				PubKey:   d.PubKey,
+				Mnemonic: ctx.Config.GetMnemonics(chainName, d.Name),
+			})
 			ux.Info("- name: %s", d.Name)
 			ux.Info("  type: %s", d.KeyType)
 			ux.Info("  address: %s", d.Address)
@@ -36,4 +55,5 @@ func Keys(ctx context.Context) {
 		}
 		doneNetworkNames = append(doneNetworkNames, chainName)
 	}
+	ux.JSON(results)
 }
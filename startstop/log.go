@@ -5,29 +5,211 @@ import (
 	"github.com/hpcloud/tail"
 	"github.com/spf13/viper"
 	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
 	"tm/tm/v2/consts"
 	"tm/tm/v2/context"
+	"tm/tm/v2/execute"
+	"tm/tm/v2/logs"
+	"tm/tm/v2/ux"
 )
 
+// logColors are the ANSI colors of the log line prefixes, assigned to the sources in turn.
+var logColors = []string{"\x1b[36m", "\x1b[32m", "\x1b[33m", "\x1b[35m", "\x1b[34m", "\x1b[31m"}
+
+// logSource is a node or relayer instance log file.
+type logSource struct {
+	name string
+	file string
+}
+
+// logLine is a line of a log file with its source and its arrival time.
+type logLine struct {
+	source  int
+	text    string
+	time    time.Time
+	arrived time.Time
+}
+
+// Log prints the logs of the nodes and relayer instances in the input. All logs are tailed at the same time and merged
+// in arrival order, or in timestamp order with --sort-by-time. Each line is prefixed with the node or relayer name.
+// Lines can be filtered by level, module, regular expression and time.
 func Log(ctx context.Context) {
+	filter, err := logs.NewFilter(viper.GetString("level"), viper.GetString("module"), viper.GetString("grep"), viper.GetString("since"))
+	if err != nil {
+		ux.Fatal("%s", err)
+	}
+
+	run := viper.GetInt("run")
+	if run > 0 {
+		ux.Fatal("invalid run %d, use 0 for the current log and negative numbers for archived logs", run)
+	}
+	var sources []logSource
+	for _, fullNodename := range ctx.Input {
+		sources = appendLogSource(sources, fullNodename, ctx.Config.GetHome(fullNodename), run)
+	}
+	for _, relayer := range getRelayers(ctx) {
+		sources = appendLogSource(sources, relayer.name, relayer.home, run)
+	}
+
+	lines := tailSources(sources, filter)
+	if viper.GetBool("sort-by-time") {
+		lines = sortLines(lines, len(sources))
+	}
+
+	prefixes := getLogPrefixes(sources)
+	for line := range lines {
+		fmt.Printf("%s%s\n", prefixes[line.source], line.text)
+	}
+}
+
+// appendLogSource adds the log of a run in the home folder to the sources. Run 0 is the current log, -1 is the newest
+// archived log and so on.
+func appendLogSource(sources []logSource, name string, home string, run int) []logSource {
+	if run == 0 {
+		return append(sources, logSource{name: name, file: consts.GetLog(home)})
+	}
+	archives := execute.GetLogArchives(home)
+	if len(archives)+run < 0 {
+		ux.Warn("%s has %d archived log(s), run %d not found", name, len(archives), run)
+		return sources
+	}
+	return append(sources, logSource{name: name, file: archives[len(archives)+run]})
+}
+
+// getLogRotation returns the log rotation settings of the config.
+func getLogRotation(ctx context.Context) execute.LogRotation {
+	return execute.LogRotation{
+		MaxSize:   ctx.Config.GetLogMaxSize(),
+		Retention: ctx.Config.GetLogRetention(),
+	}
+}
+
+// tailSources tails all log files at the same time and sends the lines that match the filter to the returned channel.
+// Continuation lines, e.g. stack traces, are sent if the line they belong to matches. The channel is closed when all
+// tails end.
+func tailSources(sources []logSource, filter logs.Filter) <-chan logLine {
 	follow := viper.GetBool("follow")
 	followAndRetry := viper.GetBool("follow-and-retry")
 	var location *tail.SeekInfo
-	if follow || followAndRetry {
+	// Following a log since a time starts at the beginning of the file.
+	if (follow || followAndRetry) && filter.Since.IsZero() {
 		location = &tail.SeekInfo{
 			Offset: -15 * 120, // about 120 char per line, 15 lines
 			Whence: io.SeekEnd,
 		}
 	}
-	for _, fullNodename := range ctx.Input {
-		home := ctx.Config.GetHome(fullNodename)
-		t, _ := tail.TailFile(consts.GetLog(home), tail.Config{
+	lines := make(chan logLine)
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		// Followed logs are reopened when the log writer archives them.
+		t, err := tail.TailFile(source.file, tail.Config{
 			Location: location,
-			ReOpen:   followAndRetry,
+			ReOpen:   follow || followAndRetry,
 			Follow:   follow || followAndRetry,
+			Logger:   tail.DiscardingLogger,
 		})
-		for line := range t.Lines {
-			fmt.Println(line.Text)
+		if err != nil {
+			ux.Warn("could not open log of %s: %s", source.name, err)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, t *tail.Tail) {
+			defer wg.Done()
+			matcher := logs.NewMatcher(filter)
+			for line := range t.Lines {
+				entry := logs.Parse(line.Text)
+				if matcher.Match(entry) {
+					lines <- logLine{source: i, text: line.Text, time: entry.Time, arrived: time.Now()}
+				}
+			}
+		}(i, t)
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+	return lines
+}
+
+// sortLines orders the lines by their timestamp. Lines are held back for consts.LogSortWindow, so lines of other
+// sources that arrive later can be placed before them. Lines without a timestamp keep the timestamp of the previous
+// line of the same source.
+func sortLines(lines <-chan logLine, sourceCount int) <-chan logLine {
+	sorted := make(chan logLine)
+	go func() {
+		defer close(sorted)
+		lastTimes := make([]time.Time, sourceCount)
+		var buffer []logLine
+		ticker := time.NewTicker(consts.LogSortWindow / 10)
+		defer ticker.Stop()
+		flush := func(until time.Time) {
+			sort.SliceStable(buffer, func(i, j int) bool {
+				return buffer[i].time.Before(buffer[j].time)
+			})
+			var held []logLine
+			for _, line := range buffer {
+				if line.arrived.After(until) {
+					held = append(held, line)
+					continue
+				}
+				// Lines arriving later must not be printed before lines that are held back.
+				if len(held) > 0 {
+					held = append(held, line)
+					continue
+				}
+				sorted <- line
+			}
+			buffer = held
 		}
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					flush(time.Now())
+					return
+				}
+				if line.time.IsZero() {
+					line.time = lastTimes[line.source]
+				} else {
+					lastTimes[line.source] = line.time
+				}
+				buffer = append(buffer, line)
+			case <-ticker.C:
+				flush(time.Now().Add(-consts.LogSortWindow))
+			}
+		}
+	}()
+	return sorted
+}
+
+// getLogPrefixes returns the aligned line prefixes of the sources. Prefixes are colored if the output is a terminal.
+func getLogPrefixes(sources []logSource) []string {
+	width := 0
+	for _, source := range sources {
+		if len(source.name) > width {
+			width = len(source.name)
+		}
+	}
+	color := isTerminal(os.Stdout)
+	var prefixes []string
+	for i, source := range sources {
+		prefix := fmt.Sprintf("%-*s | ", width, source.name)
+		if color {
+			prefix = logColors[i%len(logColors)] + prefix + ansiReset
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes
+}
+
+// isTerminal returns true if the file is a terminal.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
 	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
@@ -0,0 +1,52 @@
+package startstop
+
+import (
+	"tm/tm/v2/context"
+	"tm/tm/v2/execute"
+)
+
+// relayer describes a Hermes or Go relayer instance selected in the input.
+type relayer struct {
+	name       string
+	home       string
+	configFile string
+	nodes      []string
+	start      func() (int, error)
+}
+
+// getRelayers returns the Hermes and Go relayer instances selected in the input.
+func getRelayers(ctx context.Context) []relayer {
+	var result []relayer
+	rotation := getLogRotation(ctx)
+	for _, hermesName := range ctx.HermesInput {
+		_, hermes := ctx.Config.FindHermes(hermesName)
+		binary := ctx.Config.GetHermesBinary(hermesName)
+		configFile := ctx.Config.GetHermesConfig(hermesName)
+		home := ctx.Config.GetHermesHome(hermesName)
+		result = append(result, relayer{
+			name:       hermesName,
+			home:       home,
+			configFile: configFile,
+			nodes:      hermes.Nodes,
+			start: func() (int, error) {
+				return execute.StartHermes(binary, configFile, home, rotation)
+			},
+		})
+	}
+	for _, relayerName := range ctx.RelayerInput {
+		_, relayerConfig := ctx.Config.FindRelayer(relayerName)
+		binary := ctx.Config.GetRelayerBinary(relayerName)
+		home := ctx.Config.GetRelayerHome(relayerName)
+		debugPort := ctx.Config.GetRelayerDebugPort(relayerName)
+		result = append(result, relayer{
+			name:       relayerName,
+			home:       home,
+			configFile: ctx.Config.GetRelayerConfig(relayerName),
+			nodes:      relayerConfig.Nodes,
+			start: func() (int, error) {
+				return execute.StartRelayer(binary, home, debugPort, rotation)
+			},
+		})
+	}
+	return result
+}
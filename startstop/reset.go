@@ -1,29 +1,98 @@
 package startstop
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"tm/tm/v2/consts"
 	"tm/tm/v2/context"
 	"tm/tm/v2/execute"
+	"tm/tm/v2/utils"
 	"tm/tm/v2/ux"
 )
 
-func Reset(ctx context.Context) {
+func Reset(ctx context.Context) []ux.Result {
+	var results []ux.Result
+	// Running nodes are restarted together after the reset, because validators of the same chain only produce blocks
+	// together.
+	restartedPids := make(map[string]int)
+	var restartedNodes []string
 	for _, fullNodename := range ctx.Input {
+		// Stop waits for the process to exit, so the database is not in use during the reset.
 		pid := execute.GetPid(ctx.Config.GetHome(fullNodename))
 		if pid != nil {
-			err := execute.Stop(ctx.Config.GetHome(fullNodename))
-			if err != nil {
-				ux.Info("✘ %s not stopped: %s.", fullNodename, err)
+			result := stop(ctx, fullNodename, ctx.Config.GetHome(fullNodename))
+			if result.Failed() {
+				results = append(results, result)
 				continue
 			}
 		}
 		execute.Reset(ctx.Config.GetBinary(fullNodename), ctx.Config.GetHome(fullNodename))
 		if pid != nil {
-			_, err := execute.Start(ctx.Config.GetBinary(fullNodename), ctx.Config.GetHome(fullNodename))
+			pidInt, err := execute.Start(ctx.Config.GetBinary(fullNodename), ctx.Config.GetHome(fullNodename), getLogRotation(ctx))
 			if err != nil {
 				ux.Info("✘ %s not started, %s.", fullNodename, err)
+				results = append(results, ux.Result{Name: fullNodename, Result: ux.ResultFailed, Error: err.Error()})
 				continue
 			}
+			restartedPids[fullNodename] = pidInt
+			restartedNodes = append(restartedNodes, fullNodename)
+			continue
 		}
 		ux.Info("✔ %s reset.", fullNodename)
+		results = append(results, ux.Result{Name: fullNodename, Result: "reset"})
+	}
+	removeResetChannels(ctx)
+
+	deadline := time.Now().Add(ctx.Config.GetStartupTimeout())
+	for _, fullNodename := range restartedNodes {
+		pid := restartedPids[fullNodename]
+		err := execute.WaitForBlocks(ctx.Config.GetHome(fullNodename), ctx.Config.GetRPCPort(fullNodename), deadline)
+		switch {
+		case errors.Is(err, execute.ErrNotProducingBlocks):
+			ux.Info("⚠ %s reset, %s.", fullNodename, err)
+			results = append(results, ux.Result{Name: fullNodename, Result: ux.ResultStalled, Pid: pid, Error: err.Error()})
+		case err != nil:
+			ux.Info("✘ %s not started, %s.", fullNodename, err)
+			results = append(results, ux.Result{Name: fullNodename, Result: ux.ResultFailed, Error: err.Error()})
+		default:
+			ux.Info("✔ %s reset.", fullNodename)
+			results = append(results, ux.Result{Name: fullNodename, Result: "reset", Pid: pid})
+		}
+	}
+	return results
+}
+
+// removeResetChannels removes the IBC channel details of the chains whose validators were all reset, because the
+// channels do not exist on chain anymore. The details stored on the counterparty chains are removed too, so the
+// channels are set up again on the next start.
+func removeResetChannels(ctx context.Context) {
+	for _, chainName := range ctx.AllChainNames {
+		reset := true
+		for nodeName, node := range ctx.Config.Chains[chainName].Nodes {
+			if node.Validator && !utils.Contains(ctx.Input, fmt.Sprintf("%s.%s", chainName, nodeName)) {
+				reset = false
+				break
+			}
+		}
+		if !reset {
+			continue
+		}
+		ibcDir := consts.GetIBCDir(ctx.Config.GetChainHome(chainName))
+		if err := os.RemoveAll(ibcDir); err != nil {
+			ux.Warn("could not remove IBC channels of %s: %s", chainName, err)
+		}
+		for _, counterpartyChainName := range ctx.AllChainNames {
+			if counterpartyChainName == chainName {
+				continue
+			}
+			files, _ := filepath.Glob(consts.GetChannel(ctx.Config.GetChainHome(counterpartyChainName), "*", chainName))
+			for _, file := range files {
+				ux.Debug("removing IBC channel %s", file)
+				_ = os.Remove(file)
+			}
+		}
 	}
 }
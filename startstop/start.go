@@ -1,25 +1,94 @@
 package startstop
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
 	"tm/tm/v2/context"
 	"tm/tm/v2/execute"
+	"tm/tm/v2/ibc"
 	"tm/tm/v2/initialize"
 	"tm/tm/v2/ux"
 )
 
-func Start(ctx context.Context) {
+func Start(ctx context.Context) []ux.Result {
+	var results []ux.Result
+	// All processes are started first, because validators of the same chain only produce blocks together.
+	startedPids := make(map[string]int)
+	var startedNodes []string
 	for _, fullNodename := range ctx.Input {
 		pid := execute.GetPid(ctx.Config.GetHome(fullNodename))
 		if pid != nil {
 			ux.Info("⚠ %s skipped, PID %d.", fullNodename, *pid)
+			results = append(results, ux.Result{Name: fullNodename, Result: "skipped", Pid: *pid})
 			continue
 		}
 		initialize.ValidateGenesis(ctx, fullNodename)
-		pidInt, err := execute.Start(ctx.Config.GetBinary(fullNodename), ctx.Config.GetHome(fullNodename))
+		pidInt, err := execute.Start(ctx.Config.GetBinary(fullNodename), ctx.Config.GetHome(fullNodename), getLogRotation(ctx))
 		if err != nil {
 			ux.Info("✘ %s not started, %s.", fullNodename, err)
+			results = append(results, ux.Result{Name: fullNodename, Result: ux.ResultFailed, Error: err.Error()})
 			continue
 		}
-		ux.Info("✔ %s started, PID %d.", fullNodename, pidInt)
+		startedPids[fullNodename] = pidInt
+		startedNodes = append(startedNodes, fullNodename)
 	}
+
+	deadline := time.Now().Add(ctx.Config.GetStartupTimeout())
+	for _, fullNodename := range startedNodes {
+		pid := startedPids[fullNodename]
+		err := execute.WaitForBlocks(ctx.Config.GetHome(fullNodename), ctx.Config.GetRPCPort(fullNodename), deadline)
+		switch {
+		case errors.Is(err, execute.ErrNotProducingBlocks):
+			ux.Info("⚠ %s %s, PID %d.", fullNodename, err, pid)
+			results = append(results, ux.Result{Name: fullNodename, Result: ux.ResultStalled, Pid: pid, Error: err.Error()})
+		case err != nil:
+			ux.Info("✘ %s not started, %s.", fullNodename, err)
+			results = append(results, ux.Result{Name: fullNodename, Result: ux.ResultFailed, Error: err.Error()})
+		default:
+			ux.Info("✔ %s started, PID %d.", fullNodename, pid)
+			results = append(results, ux.Result{Name: fullNodename, Result: "started", Pid: pid})
+		}
+	}
+
+	// Relayer instances start after the nodes, because they connect to them.
+	for _, relayer := range getRelayers(ctx) {
+		results = append(results, startRelayer(ctx, relayer))
+	}
+
+	// Set up the IBC channels defined in the config.
+	results = append(results, ibc.ConnectConfigured(ctx)...)
+	return results
+}
+
+// startRelayer starts a relayer instance if all the nodes it connects to are running.
+func startRelayer(ctx context.Context, relayer relayer) ux.Result {
+	pid := execute.GetPid(relayer.home)
+	if pid != nil {
+		ux.Info("⚠ %s skipped, PID %d.", relayer.name, *pid)
+		return ux.Result{Name: relayer.name, Result: "skipped", Pid: *pid}
+	}
+	var err error
+	for _, fullNodename := range relayer.nodes {
+		if execute.GetPid(ctx.Config.GetHome(fullNodename)) == nil {
+			err = fmt.Errorf("%s is not running", fullNodename)
+			break
+		}
+	}
+	if err == nil {
+		if _, statErr := os.Stat(relayer.configFile); statErr != nil {
+			err = fmt.Errorf("config %s not found", relayer.configFile)
+		}
+	}
+	var pidInt int
+	if err == nil {
+		pidInt, err = relayer.start()
+	}
+	if err != nil {
+		ux.Info("✘ %s not started, %s.", relayer.name, err)
+		return ux.Result{Name: relayer.name, Result: ux.ResultFailed, Error: err.Error()}
+	}
+	ux.Info("✔ %s started, PID %d.", relayer.name, pidInt)
+	return ux.Result{Name: relayer.name, Result: "started", Pid: pidInt}
 }
@@ -1,19 +1,252 @@
 package startstop
 
 import (
+	"bytes"
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"tm/tm/v2/consts"
 	"tm/tm/v2/context"
 	"tm/tm/v2/execute"
+	"tm/tm/v2/rpc"
 	"tm/tm/v2/ux"
 )
 
+// nodeStatus holds the process and RPC state of a node. A node is unreachable if its /status query fails. Failures of
+// the other queries are kept separately.
+type nodeStatus struct {
+	name       string
+	validator  bool
+	pid        *int
+	pidErr     error
+	status     *rpc.Status
+	netInfo    *rpc.NetInfo
+	commit     *rpc.Commit
+	rpcErr     error
+	netInfoErr error
+	commitErr  error
+}
+
+// getNodeStatus queries the process and, if it is running, the RPC state of a node.
+func getNodeStatus(ctx context.Context, fullNodename string) nodeStatus {
+	_, node := ctx.Config.FindNode(fullNodename)
+	result := nodeStatus{
+		name:      fullNodename,
+		validator: node.Validator,
+	}
+	result.pid, result.pidErr = execute.CheckPid(ctx.Config.GetHome(fullNodename))
+	if result.pid == nil {
+		return result
+	}
+	port := ctx.Config.GetRPCPort(fullNodename)
+	result.status, result.rpcErr = rpc.GetStatus(port)
+	if result.rpcErr != nil {
+		return result
+	}
+	result.netInfo, result.netInfoErr = rpc.GetNetInfo(port)
+	// The commit of the latest block only has the signatures this node has seen, the canonical commit is in the next block.
+	if height := result.status.SyncInfo.LatestBlockHeight; height > 1 {
+		result.commit, result.commitErr = rpc.GetCommitAt(port, height-1)
+	}
+	return result
+}
+
+// state returns a short description of the node process state.
+func (n nodeStatus) state() string {
+	switch {
+	case n.pidErr != nil:
+		return "stale"
+	case n.pid == nil:
+		return "stopped"
+	case n.rpcErr != nil:
+		return "unreachable"
+	default:
+		return "running"
+	}
+}
+
+// nodeStatusResult is the machine-readable status of a node.
+type nodeStatusResult struct {
+	Name        string     `json:"name"`
+	State       string     `json:"state"`
+	Pid         int        `json:"pid,omitempty"`
+	Height      uint64     `json:"height,omitempty"`
+	BlockTime   *time.Time `json:"block_time,omitempty"`
+	CatchingUp  *bool      `json:"catching_up,omitempty"`
+	VotingPower *int64     `json:"voting_power,omitempty"`
+	Peers       *uint      `json:"peers,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// chainStatusResult is the machine-readable status of a chain.
+type chainStatusResult struct {
+	Name     string             `json:"name"`
+	Nodes    []nodeStatusResult `json:"nodes"`
+	Problems []string           `json:"problems,omitempty"`
+}
+
+// statusResult is the machine-readable output of the status command.
+type statusResult struct {
+	Chains   []chainStatusResult `json:"chains"`
+	Relayers []ux.Result         `json:"relayers,omitempty"`
+}
+
+// result returns the machine-readable status of the node.
+func (n nodeStatus) result() nodeStatusResult {
+	result := nodeStatusResult{
+		Name:  n.name,
+		State: n.state(),
+	}
+	if n.pid != nil {
+		result.Pid = *n.pid
+	}
+	if n.pidErr != nil {
+		result.Error = n.pidErr.Error()
+	}
+	var queryErrors []string
+	for _, err := range []error{n.rpcErr, n.netInfoErr, n.commitErr} {
+		if err != nil {
+			queryErrors = append(queryErrors, err.Error())
+		}
+	}
+	if len(queryErrors) > 0 {
+		result.Error = strings.Join(queryErrors, "; ")
+	}
+	if n.status != nil {
+		result.Height = n.status.SyncInfo.LatestBlockHeight
+		result.BlockTime = &n.status.SyncInfo.LatestBlockTime
+		result.CatchingUp = &n.status.SyncInfo.CatchingUp
+		result.VotingPower = &n.status.ValidatorInfo.VotingPower
+	}
+	if n.netInfo != nil {
+		result.Peers = &n.netInfo.NPeers
+	}
+	return result
+}
+
 func Status(ctx context.Context) {
+	// Group nodes by chain
+	chainNodes := make(map[string][]string)
+	var chainNames []string
 	for _, fullNodename := range ctx.Input {
-		pid := execute.GetPid(ctx.Config.GetHome(fullNodename))
-		if pid != nil {
-			ux.Info("✔ %s running, PID %s.", fullNodename, strconv.Itoa(*pid))
+		chainName := strings.Split(fullNodename, ".")[0]
+		if _, ok := chainNodes[chainName]; !ok {
+			chainNames = append(chainNames, chainName)
+		}
+		chainNodes[chainName] = append(chainNodes[chainName], fullNodename)
+	}
+	sort.Strings(chainNames)
+
+	var result statusResult
+	for _, chainName := range chainNames {
+		sort.Strings(chainNodes[chainName])
+		var statuses []nodeStatus
+		for _, fullNodename := range chainNodes[chainName] {
+			statuses = append(statuses, getNodeStatus(ctx, fullNodename))
+		}
+		problems := getChainProblems(statuses)
+		printChainStatus(chainName, statuses, problems)
+		chainResult := chainStatusResult{
+			Name:     chainName,
+			Problems: problems,
+		}
+		for _, nodeStatus := range statuses {
+			chainResult.Nodes = append(chainResult.Nodes, nodeStatus.result())
+		}
+		result.Chains = append(result.Chains, chainResult)
+	}
+
+	for _, relayer := range getRelayers(ctx) {
+		pid, err := execute.CheckPid(relayer.home)
+		if err != nil {
+			ux.Info("⚠ %s stopped, %s.", relayer.name, err)
+			result.Relayers = append(result.Relayers, ux.Result{Name: relayer.name, Result: "stale", Error: err.Error()})
+		} else if pid != nil {
+			ux.Info("✔ %s running, PID %s.", relayer.name, strconv.Itoa(*pid))
+			result.Relayers = append(result.Relayers, ux.Result{Name: relayer.name, Result: "running", Pid: *pid})
 		} else {
-			ux.Info("✘ %s stopped.", fullNodename)
+			ux.Info("✘ %s stopped.", relayer.name)
+			result.Relayers = append(result.Relayers, ux.Result{Name: relayer.name, Result: "stopped"})
+		}
+	}
+	ux.JSON(result)
+}
+
+// getChainProblems returns the process and consensus problems of the nodes of a chain.
+func getChainProblems(statuses []nodeStatus) []string {
+	var problems []string
+	var minHeight, maxHeight uint64
+	heightFound := false
+	for _, s := range statuses {
+		if s.pidErr != nil {
+			problems = append(problems, fmt.Sprintf("%s has a %s", s.name, s.pidErr))
+		}
+		if s.rpcErr != nil {
+			problems = append(problems, fmt.Sprintf("%s RPC query failed: %s", s.name, s.rpcErr))
+		}
+		if s.netInfoErr != nil {
+			problems = append(problems, fmt.Sprintf("%s net_info query failed: %s", s.name, s.netInfoErr))
+		}
+		if s.commitErr != nil {
+			problems = append(problems, fmt.Sprintf("%s commit query failed: %s", s.name, s.commitErr))
 		}
+		if s.status == nil {
+			continue
+		}
+		h := s.status.SyncInfo.LatestBlockHeight
+		if !heightFound || h < minHeight {
+			minHeight = h
+		}
+		if !heightFound || h > maxHeight {
+			maxHeight = h
+		}
+		heightFound = true
+		if s.validator {
+			switch {
+			case s.status.ValidatorInfo.VotingPower == 0:
+				problems = append(problems, fmt.Sprintf("%s is a validator without voting power", s.name))
+			case s.commit != nil && !s.commit.Signed(s.status.ValidatorInfo.Address):
+				problems = append(problems, fmt.Sprintf("%s did not sign block %d", s.name, s.commit.SignedHeader.Commit.Height))
+			}
+		}
+	}
+	if heightFound && maxHeight-minHeight > consts.MaxHeightDivergence {
+		problems = append(problems, fmt.Sprintf("heights diverge between %d and %d", minHeight, maxHeight))
+	}
+	return problems
+}
+
+// printChainStatus prints the status table of the nodes of a chain, followed by the problems found.
+func printChainStatus(chainName string, statuses []nodeStatus, problems []string) {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NODE\tSTATE\tPID\tHEIGHT\tBLOCK TIME\tCATCHING UP\tVOTING POWER\tPEERS")
+	for _, s := range statuses {
+		nodeName := strings.Split(s.name, ".")[1]
+		pid, height, blockTime, catchingUp, votingPower, peers := "-", "-", "-", "-", "-", "-"
+		if s.pid != nil {
+			pid = strconv.Itoa(*s.pid)
+		}
+		if s.status != nil {
+			height = strconv.FormatUint(s.status.SyncInfo.LatestBlockHeight, 10)
+			blockTime = s.status.SyncInfo.LatestBlockTime.Format("2006-01-02 15:04:05")
+			catchingUp = strconv.FormatBool(s.status.SyncInfo.CatchingUp)
+			votingPower = strconv.FormatInt(s.status.ValidatorInfo.VotingPower, 10)
+		}
+		if s.netInfo != nil {
+			peers = strconv.FormatUint(uint64(s.netInfo.NPeers), 10)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", nodeName, s.state(), pid, height, blockTime, catchingUp, votingPower, peers)
+	}
+	_ = w.Flush()
+
+	ux.Info("%s", chainName)
+	ux.Info("%s", strings.TrimSuffix(buf.String(), "\n"))
+	for _, problem := range problems {
+		ux.Info("⚠ %s.", problem)
 	}
+	ux.Info("")
 }
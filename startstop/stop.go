@@ -6,16 +6,38 @@ import (
 	"tm/tm/v2/ux"
 )
 
-func Stop(ctx context.Context) {
+func Stop(ctx context.Context) []ux.Result {
+	var results []ux.Result
+	// Relayer instances stop before the nodes, so they do not report the nodes missing.
+	for _, relayer := range getRelayers(ctx) {
+		results = append(results, stop(ctx, relayer.name, relayer.home))
+	}
+
 	for _, fullNodename := range ctx.Input {
-		pid := execute.GetPid(ctx.Config.GetHome(fullNodename))
-		if pid != nil {
-			err := execute.Stop(ctx.Config.GetHome(fullNodename))
-			if err != nil {
-				ux.Info("✘ %s not stopped: %s.", fullNodename, err)
-				continue
-			}
-		}
-		ux.Info("✔ %s stopped.", fullNodename)
+		results = append(results, stop(ctx, fullNodename, ctx.Config.GetHome(fullNodename)))
+	}
+	return results
+}
+
+// stop stops the process in the home folder and reports how it was stopped.
+func stop(ctx context.Context, name string, home string) ux.Result {
+	pid := execute.GetPid(home)
+	if pid == nil {
+		// A crashed node waiting for a restart by a supervisor is not restarted anymore.
+		execute.MarkStopped(home)
+		ux.Info("⚠ %s skipped, not running.", name)
+		return ux.Result{Name: name, Result: "skipped"}
+	}
+	signal, err := execute.Stop(home, ctx.Config.GetStopTimeout())
+	if err != nil {
+		ux.Info("✘ %s not stopped: %s.", name, err)
+		return ux.Result{Name: name, Result: ux.ResultFailed, Error: err.Error()}
+	}
+	result := ux.Result{Name: name, Result: "stopped", Pid: *pid, Signal: signal}
+	if signal == "" {
+		ux.Info("✔ %s stopped.", name)
+	} else {
+		ux.Info("✔ %s stopped with %s.", name, signal)
 	}
+	return result
 }
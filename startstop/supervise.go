@@ -0,0 +1,131 @@
+package startstop
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"tm/tm/v2/consts"
+	"tm/tm/v2/context"
+	"tm/tm/v2/execute"
+	"tm/tm/v2/ux"
+)
+
+// supervised holds the restart state of a supervised node.
+type supervised struct {
+	pid         int
+	restarts    uint
+	backoff     time.Duration
+	nextRestart *time.Time
+}
+
+// Supervise stays in the foreground and restarts the running nodes of the input when they exit unexpectedly. Nodes
+// stopped by tm (they have a stop marker) are not supervised anymore. It returns when no supervised node is left or
+// on interrupt.
+func Supervise(ctx context.Context) {
+	nodes := make(map[string]*supervised)
+	for _, fullNodename := range ctx.Input {
+		pid := execute.GetPid(ctx.Config.GetHome(fullNodename))
+		if pid != nil {
+			nodes[fullNodename] = &supervised{
+				pid:     *pid,
+				backoff: consts.RestartBackoff,
+			}
+		}
+	}
+	if len(nodes) == 0 {
+		ux.Info("⚠ no running nodes to supervise.")
+		return
+	}
+	ux.Info("supervising %d node(s), press Ctrl-C to exit.", len(nodes))
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	ticker := time.NewTicker(consts.SupervisePollInterval)
+	defer ticker.Stop()
+	for len(nodes) > 0 {
+		select {
+		case <-interrupt:
+			ux.Info("supervisor stopped, nodes are left running.")
+			return
+		case <-ticker.C:
+		}
+		for _, fullNodename := range ctx.Input {
+			node, ok := nodes[fullNodename]
+			if !ok {
+				continue
+			}
+			if !superviseNode(ctx, fullNodename, node) {
+				delete(nodes, fullNodename)
+			}
+		}
+	}
+}
+
+// superviseNode checks a node and restarts it if necessary. It returns false if the node should not be supervised
+// anymore.
+func superviseNode(ctx context.Context, fullNodename string, node *supervised) bool {
+	home := ctx.Config.GetHome(fullNodename)
+	if execute.IsStopped(home) {
+		ux.Info("⚠ %s stopped, not supervised anymore.", fullNodename)
+		return false
+	}
+	// The PID file of a crashed node might have been removed by a PID check of another command.
+	pid := execute.ReadPid(home)
+	if pid != nil && *pid != node.pid {
+		// The node was restarted outside the supervisor.
+		node.pid = *pid
+	}
+	if execute.IsRunning(node.pid) {
+		return true
+	}
+	if node.nextRestart == nil {
+		if node.restarts >= ctx.Config.GetMaxRestarts() {
+			ux.Info("✘ %s exited, PID %d, restart limit %d reached.", fullNodename, node.pid, node.restarts)
+			recordRestart(home, fmt.Sprintf("PID %d exited, restart limit %d reached", node.pid, node.restarts))
+			_ = os.Remove(consts.GetPid(home))
+			return false
+		}
+		nextRestart := time.Now().Add(node.backoff)
+		node.nextRestart = &nextRestart
+		ux.Info("⚠ %s exited, PID %d, restarting in %s.", fullNodename, node.pid, node.backoff)
+		return true
+	}
+	if time.Now().Before(*node.nextRestart) {
+		return true
+	}
+
+	node.nextRestart = nil
+	node.restarts++
+	node.backoff *= 2
+	if node.backoff > consts.MaxRestartBackoff {
+		node.backoff = consts.MaxRestartBackoff
+	}
+	newPid, err := execute.Start(ctx.Config.GetBinary(fullNodename), home, getLogRotation(ctx))
+	if err != nil {
+		ux.Info("✘ %s not restarted, %s.", fullNodename, err)
+		recordRestart(home, fmt.Sprintf("PID %d exited, restart %d failed: %s", node.pid, node.restarts, err))
+		return true
+	}
+	ux.Info("✔ %s restarted, PID %d.", fullNodename, newPid)
+	recordRestart(home, fmt.Sprintf("PID %d exited, restart %d, PID %d", node.pid, node.restarts, newPid))
+	node.pid = newPid
+	return true
+}
+
+// recordRestart appends a timestamped line to the restarts file in the node home.
+func recordRestart(home string, message string) {
+	file, err := os.OpenFile(consts.GetRestarts(home), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		ux.Warn("could not open %s: %s", consts.GetRestarts(home), err)
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	_, err = fmt.Fprintf(file, "%s %s\n", time.Now().Format(time.RFC3339), message)
+	if err != nil {
+		ux.Warn("could not write %s: %s", consts.GetRestarts(home), err)
+	}
+}
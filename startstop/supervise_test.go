@@ -0,0 +1,109 @@
+//go:build !windows
+
+package startstop
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+	"tm/tm/v2/config"
+	"tm/tm/v2/consts"
+	"tm/tm/v2/context"
+	"tm/tm/v2/execute"
+)
+
+// TestMain runs the test binary as the log writer that execute.Start spawns with os.Executable.
+func TestMain(m *testing.M) {
+	if len(os.Args) > 2 && os.Args[1] == "log-writer" {
+		_ = execute.WriteLog(os.Stdin, os.Args[2], execute.LogRotation{})
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// waitForExit waits until the process exited and was reaped.
+func waitForExit(t *testing.T, pid int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for execute.IsRunning(pid) {
+		if time.Now().After(deadline) {
+			t.Fatalf("PID %d did not exit", pid)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSuperviseCrashedNode(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "node")
+	if err := ioutil.WriteFile(binary, []byte("#!/bin/sh\nexec sleep 30\n"), fs.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	home := filepath.Join(dir, "home")
+	if err := os.Mkdir(home, fs.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Context{
+		Input: []string{"test.node"},
+		Config: config.Config{
+			MaxRestarts: 3,
+			Chains: map[string]*config.ChainConfig{
+				"test": {Binary: binary, Nodes: map[string]*config.Node{"node": {Home: home}}},
+			},
+		},
+	}
+
+	pid, err := execute.Start(binary, home, getLogRotation(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := &supervised{pid: pid, backoff: consts.RestartBackoff}
+	t.Cleanup(func() {
+		_ = syscall.Kill(-node.pid, syscall.SIGKILL)
+	})
+	if !superviseNode(ctx, "test.node", node) || node.nextRestart != nil {
+		t.Fatal("running node not supervised")
+	}
+
+	// A crash during the backoff is followed by a command that checks the PID and removes the PID file.
+	if err = syscall.Kill(-pid, syscall.SIGKILL); err != nil {
+		t.Fatal(err)
+	}
+	waitForExit(t, pid)
+	if !superviseNode(ctx, "test.node", node) || node.nextRestart == nil {
+		t.Fatal("crashed node not scheduled for a restart")
+	}
+	if checked, _ := execute.CheckPid(home); checked != nil {
+		t.Fatalf("crashed node has PID %d", *checked)
+	}
+	if _, err = os.Stat(consts.GetPid(home)); !os.IsNotExist(err) {
+		t.Fatal("PID file of the crashed node not removed")
+	}
+	if !superviseNode(ctx, "test.node", node) {
+		t.Fatal("crashed node not supervised after the PID check")
+	}
+
+	past := time.Now().Add(-time.Second)
+	node.nextRestart = &past
+	if !superviseNode(ctx, "test.node", node) {
+		t.Fatal("restarted node not supervised")
+	}
+	if node.pid == pid || node.restarts != 1 {
+		t.Fatalf("node not restarted, PID %d, %d restarts", node.pid, node.restarts)
+	}
+	if restarted := execute.GetPid(home); restarted == nil || *restarted != node.pid {
+		t.Fatalf("PID file not written for the restarted PID %d", node.pid)
+	}
+
+	// An intentional stop ends the supervision.
+	if _, err = execute.Stop(home, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if superviseNode(ctx, "test.node", node) {
+		t.Fatal("stopped node still supervised")
+	}
+}
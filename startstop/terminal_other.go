@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package startstop
+
+import "errors"
+
+// makeRaw is not supported on this system.
+func makeRaw(fd int) (func(), error) {
+	return nil, errors.New("terminal raw mode is not supported on this system")
+}
+
+// getTerminalSize is not supported on this system.
+func getTerminalSize(fd int) (int, int, error) {
+	return 0, 0, errors.New("terminal size is not supported on this system")
+}
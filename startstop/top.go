@@ -0,0 +1,423 @@
+package startstop
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+	"tm/tm/v2/consts"
+	"tm/tm/v2/context"
+	"tm/tm/v2/execute"
+	"tm/tm/v2/rpc"
+	"tm/tm/v2/ux"
+)
+
+// ANSI escape sequences used by the dashboard.
+const (
+	ansiAlternateScreen = "\x1b[?1049h"
+	ansiMainScreen      = "\x1b[?1049l"
+	ansiHideCursor      = "\x1b[?25l"
+	ansiShowCursor      = "\x1b[?25h"
+	ansiHome            = "\x1b[H"
+	ansiClearLine       = "\x1b[K"
+	ansiClearBelow      = "\x1b[J"
+	ansiReverse         = "\x1b[7m"
+	ansiBold            = "\x1b[1m"
+	ansiReset           = "\x1b[0m"
+)
+
+// topActions are the progress messages of the actions of the dashboard by key.
+var topActions = map[string]string{
+	"s": "starting",
+	"x": "stopping",
+	"r": "resetting",
+}
+
+// Key presses handled by the dashboard.
+const (
+	keyUp     = "\x1b[A"
+	keyDown   = "\x1b[B"
+	keyCtrlC  = "\x03"
+	keyEscape = "\x1b"
+)
+
+// topRow is one line of the dashboard: a node or a relayer instance.
+type topRow struct {
+	name    string
+	home    string
+	relayer *relayer
+	status  nodeStatus
+	mempool *rpc.UnconfirmedTxs
+	usage   *execute.ProcessUsage
+}
+
+// cpuSample is a CPU time measurement of a process, used to calculate the CPU usage between two refreshes.
+type cpuSample struct {
+	pid     int
+	cpuTime time.Duration
+	at      time.Time
+}
+
+// top holds the state of the dashboard.
+type top struct {
+	ctx        context.Context
+	rows       []topRow
+	selected   int
+	showLog    bool
+	message    string
+	refreshing bool
+	samples    map[string]cpuSample
+	cpu        map[string]float64
+}
+
+// Top shows a full-screen dashboard of the nodes and relayer instances in the input, refreshed every second. The
+// selected node can be started, stopped or reset and its log can be shown.
+func Top(ctx context.Context) {
+	fd := int(os.Stdin.Fd())
+	restore, err := makeRaw(fd)
+	if err != nil {
+		ux.Fatal("could not set up terminal: %s", err)
+	}
+	defer restore()
+	fmt.Print(ansiAlternateScreen + ansiHideCursor)
+	defer fmt.Print(ansiShowCursor + ansiMainScreen)
+
+	t := &top{
+		ctx:     ctx,
+		samples: make(map[string]cpuSample),
+		cpu:     make(map[string]float64),
+	}
+	keys := make(chan string)
+	go readKeys(os.Stdin, keys)
+	updates := make(chan []topRow)
+	messages := make(chan string, 10)
+	ticker := time.NewTicker(consts.TopRefreshInterval)
+	defer ticker.Stop()
+
+	t.refresh(updates)
+	t.render()
+	for {
+		select {
+		case key, ok := <-keys:
+			if !ok || !t.handleKey(key, messages) {
+				return
+			}
+		case rows := <-updates:
+			t.update(rows)
+		case message := <-messages:
+			t.message = message
+		case <-ticker.C:
+			t.refresh(updates)
+		}
+		t.render()
+	}
+}
+
+// readKeys sends the key presses read from the terminal to the channel. It closes the channel when the input ends.
+func readKeys(input io.Reader, keys chan<- string) {
+	buf := make([]byte, 16)
+	for {
+		n, err := input.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		keys <- string(buf[:n])
+	}
+}
+
+// handleKey runs the action of a key press. It returns false if the dashboard should exit.
+func (t *top) handleKey(key string, messages chan<- string) bool {
+	switch key {
+	case "q", keyCtrlC, keyEscape:
+		return false
+	case keyUp, "k":
+		if t.selected > 0 {
+			t.selected--
+		}
+	case keyDown, "j":
+		if t.selected < len(t.rows)-1 {
+			t.selected++
+		}
+	case "l":
+		t.showLog = !t.showLog
+	case "s", "x", "r":
+		if t.selected >= len(t.rows) {
+			return true
+		}
+		row := t.rows[t.selected]
+		t.message = fmt.Sprintf("%s %s...", row.name, topActions[key])
+		go func() {
+			messages <- t.runAction(key, row)
+		}()
+	}
+	return true
+}
+
+// runAction starts, stops or resets a node or relayer instance and returns the message that describes the outcome.
+func (t *top) runAction(key string, row topRow) string {
+	switch key {
+	case "s":
+		if pid, _ := execute.CheckPid(row.home); pid != nil {
+			return fmt.Sprintf("⚠ %s skipped, PID %d.", row.name, *pid)
+		}
+		var pid int
+		var err error
+		if row.relayer != nil {
+			pid, err = row.relayer.start()
+		} else {
+			pid, err = execute.Start(t.ctx.Config.GetBinary(row.name), row.home, getLogRotation(t.ctx))
+		}
+		if err != nil {
+			return fmt.Sprintf("✘ %s not started, %s.", row.name, err)
+		}
+		return fmt.Sprintf("✔ %s started, PID %d.", row.name, pid)
+	case "x":
+		if pid, _ := execute.CheckPid(row.home); pid == nil {
+			return fmt.Sprintf("⚠ %s skipped, not running.", row.name)
+		}
+		signal, err := execute.Stop(row.home, t.ctx.Config.GetStopTimeout())
+		if err != nil {
+			return fmt.Sprintf("✘ %s not stopped: %s.", row.name, err)
+		}
+		if signal == "" {
+			return fmt.Sprintf("✔ %s stopped.", row.name)
+		}
+		return fmt.Sprintf("✔ %s stopped with %s.", row.name, signal)
+	case "r":
+		if row.relayer != nil {
+			return fmt.Sprintf("⚠ %s skipped, relayer instances cannot be reset.", row.name)
+		}
+		binary := t.ctx.Config.GetBinary(row.name)
+		pid, _ := execute.CheckPid(row.home)
+		if pid != nil {
+			_, err := execute.Stop(row.home, t.ctx.Config.GetStopTimeout())
+			if err != nil {
+				return fmt.Sprintf("✘ %s not stopped: %s.", row.name, err)
+			}
+		}
+		_, err := execute.Reset(binary, row.home)
+		if err != nil {
+			return fmt.Sprintf("✘ %s not reset: %s.", row.name, err)
+		}
+		if pid != nil {
+			_, err = execute.Start(binary, row.home, getLogRotation(t.ctx))
+			if err != nil {
+				return fmt.Sprintf("✘ %s reset, not started: %s.", row.name, err)
+			}
+		}
+		return fmt.Sprintf("✔ %s reset.", row.name)
+	}
+	return ""
+}
+
+// refresh queries the state of all rows in the background and sends them to the channel. Only one refresh runs at a
+// time, so slow RPC queries do not pile up.
+func (t *top) refresh(updates chan<- []topRow) {
+	if t.refreshing {
+		return
+	}
+	t.refreshing = true
+	var rows []topRow
+	fullNodenames := append([]string{}, t.ctx.Input...)
+	sort.Strings(fullNodenames)
+	for _, fullNodename := range fullNodenames {
+		rows = append(rows, topRow{name: fullNodename, home: t.ctx.Config.GetHome(fullNodename)})
+	}
+	for _, r := range getRelayers(t.ctx) {
+		r := r
+		rows = append(rows, topRow{name: r.name, home: r.home, relayer: &r})
+	}
+	go func() {
+		var wg sync.WaitGroup
+		for i := range rows {
+			wg.Add(1)
+			go func(row *topRow) {
+				defer wg.Done()
+				row.query(t.ctx)
+			}(&rows[i])
+		}
+		wg.Wait()
+		updates <- rows
+	}()
+}
+
+// query fills in the process and RPC state of the row.
+func (row *topRow) query(ctx context.Context) {
+	if row.relayer != nil {
+		row.status = nodeStatus{name: row.name}
+		row.status.pid, row.status.pidErr = execute.CheckPid(row.home)
+	} else {
+		row.status = getNodeStatus(ctx, row.name)
+		if row.status.status != nil {
+			row.mempool, _ = rpc.GetUnconfirmedTxs(ctx.Config.GetRPCPort(row.name))
+		}
+	}
+	if row.status.pid != nil {
+		usage, err := execute.GetProcessUsage(*row.status.pid)
+		if err == nil {
+			row.usage = &usage
+		}
+	}
+}
+
+// update replaces the rows with the results of a refresh and calculates the CPU usage since the previous refresh.
+func (t *top) update(rows []topRow) {
+	t.refreshing = false
+	now := time.Now()
+	for _, row := range rows {
+		if row.usage == nil {
+			delete(t.samples, row.name)
+			delete(t.cpu, row.name)
+			continue
+		}
+		sample := cpuSample{pid: *row.status.pid, cpuTime: row.usage.CPUTime, at: now}
+		previous, ok := t.samples[row.name]
+		if ok && previous.pid == sample.pid && now.After(previous.at) {
+			t.cpu[row.name] = float64(sample.cpuTime-previous.cpuTime) / float64(now.Sub(previous.at)) * 100
+		}
+		t.samples[row.name] = sample
+	}
+	t.rows = rows
+	if t.selected >= len(t.rows) {
+		t.selected = len(t.rows) - 1
+	}
+	if t.selected < 0 {
+		t.selected = 0
+	}
+}
+
+// render draws the dashboard.
+func (t *top) render() {
+	width, height, err := getTerminalSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = 120, 40
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tSTATE\tPID\tHEIGHT\tBLOCK TIME\tMEMPOOL\tPEERS\tCPU\tRSS")
+	for _, row := range t.rows {
+		pid, blockHeight, blockTime, mempool, peers, cpu, rss := "-", "-", "-", "-", "-", "-", "-"
+		if row.status.pid != nil {
+			pid = strconv.Itoa(*row.status.pid)
+		}
+		if row.status.status != nil {
+			blockHeight = strconv.FormatUint(row.status.status.SyncInfo.LatestBlockHeight, 10)
+			blockTime = row.status.status.SyncInfo.LatestBlockTime.Local().Format("15:04:05")
+		}
+		if row.mempool != nil {
+			mempool = strconv.FormatUint(row.mempool.Count, 10)
+		}
+		if row.status.netInfo != nil {
+			peers = strconv.FormatUint(uint64(row.status.netInfo.NPeers), 10)
+		}
+		if value, ok := t.cpu[row.name]; ok {
+			cpu = fmt.Sprintf("%.1f%%", value)
+		}
+		if row.usage != nil {
+			rss = formatBytes(row.usage.RSS)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", row.name, row.status.state(), pid, blockHeight, blockTime, mempool, peers, cpu, rss)
+	}
+	_ = w.Flush()
+	table := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+
+	var lines []string
+	lines = append(lines, ansiBold+fitLine(fmt.Sprintf("tm top  %s  ↑/↓ select  s start  x stop  r reset  l log  q quit", time.Now().Format("15:04:05")), width)+ansiReset)
+	lines = append(lines, "")
+	lines = append(lines, ansiBold+fitLine(table[0], width)+ansiReset)
+	for i, line := range table[1:] {
+		line = fitLine(line, width)
+		if i == t.selected {
+			line = ansiReverse + line + ansiReset
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "")
+	lines = append(lines, fitLine(t.message, width))
+
+	if t.showLog && t.selected < len(t.rows) {
+		row := t.rows[t.selected]
+		lines = append(lines, "")
+		lines = append(lines, ansiBold+fitLine(fmt.Sprintf("%s log", row.name), width)+ansiReset)
+		logLines := height - len(lines)
+		if logLines > 0 {
+			for _, line := range tailFile(consts.GetLog(row.home), logLines) {
+				lines = append(lines, fitLine(line, width))
+			}
+		}
+	}
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+
+	var screen strings.Builder
+	screen.WriteString(ansiHome)
+	for _, line := range lines {
+		screen.WriteString(line + ansiClearLine + "\r\n")
+	}
+	screen.WriteString(ansiClearBelow)
+	fmt.Print(screen.String())
+}
+
+// fitLine cuts the line to the terminal width.
+func fitLine(line string, width int) string {
+	runes := []rune(line)
+	if width > 0 && len(runes) > width {
+		return string(runes[:width])
+	}
+	return line
+}
+
+// tailFile returns the last lines of a file. Only the last consts.TopLogSize bytes are read.
+func tailFile(filename string, lines int) []string {
+	file, err := os.Open(filename)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	info, err := file.Stat()
+	if err != nil {
+		return []string{err.Error()}
+	}
+	offset := info.Size() - consts.TopLogSize
+	if offset < 0 {
+		offset = 0
+	}
+	data := make([]byte, info.Size()-offset)
+	_, err = file.ReadAt(data, offset)
+	if err != nil && err != io.EOF {
+		return []string{err.Error()}
+	}
+	result := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(result) > lines {
+		result = result[len(result)-lines:]
+	}
+	for i := range result {
+		result[i] = strings.TrimRight(result[i], "\r")
+	}
+	return result
+}
+
+// formatBytes returns a human-readable memory size.
+func formatBytes(size uint64) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1fG", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1fM", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1fK", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", size)
+	}
+}
@@ -0,0 +1,14 @@
+package utils
+
+// Channel describes an IBC channel and its underlying client and connection as seen from one chain.
+type Channel struct {
+	Port                     string `json:"port"`
+	Version                  string `json:"version"`
+	ClientID                 string `json:"client_id"`
+	ConnectionID             string `json:"connection_id"`
+	ChannelID                string `json:"channel_id"`
+	CounterpartyChainID      string `json:"counterparty_chain_id"`
+	CounterpartyClientID     string `json:"counterparty_client_id"`
+	CounterpartyConnectionID string `json:"counterparty_connection_id"`
+	CounterpartyChannelID    string `json:"counterparty_channel_id"`
+}
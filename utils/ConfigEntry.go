@@ -32,6 +32,17 @@ func GetConfigEntry(file string, key string) interface{} {
 	return toml.Get(key)
 }
 
+// HasConfigEntry returns true if the key is set in the file.
+func HasConfigEntry(file string, key string) bool {
+	toml := viper.New()
+	toml.SetConfigFile(file)
+	err := toml.ReadInConfig()
+	if err != nil {
+		ux.Fatal("%s, cannot get key %s", err.Error(), key)
+	}
+	return toml.IsSet(key)
+}
+
 func GetConfigEntryContentString(content string, contentType string, key string) string {
 	toml := viper.New()
 	toml.SetConfigType(contentType)
@@ -5,5 +5,5 @@ type Keys struct {
 	KeyType  string `json:"type"`
 	Address  string `json:"address"`
 	PubKey   string `json:"pubkey"`
-	Mnemonic string `json:"mnemonic, omitempty"`
+	Mnemonic string `json:"mnemonic,omitempty"`
 }
@@ -1,12 +1,39 @@
 package ux
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/spf13/viper"
 	"log"
 	"os"
+	"strings"
 )
 
+// Result is the outcome of an operation on a node, chain or relayer, used for machine-readable output.
+type Result struct {
+	Name    string `json:"name"`
+	Result  string `json:"result"`
+	Pid     int    `json:"pid,omitempty"`
+	Signal  string `json:"signal,omitempty"`
+	Address string `json:"address,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ExitCodeFailure is the exit code when some of the operations failed. Fatal errors exit with 1.
+const ExitCodeFailure = 2
+
+// Results of an operation that did not succeed.
+const (
+	ResultFailed    = "failed"
+	ResultStalled   = "stalled"
+	ResultUnhealthy = "unhealthy"
+)
+
+// Failed returns true if the operation did not succeed.
+func (r Result) Failed() bool {
+	return r.Result == ResultFailed || r.Result == ResultStalled || r.Result == ResultUnhealthy
+}
+
 func FatalRaw(format string, a ...any) {
 	log.New(os.Stderr, "", 0).Fatalf(format, a...)
 }
@@ -31,8 +58,55 @@ func Debug(format string, a ...any) {
 	}
 }
 
+// Info prints human-readable output. It is silent in quiet mode and in JSON output mode.
 func Info(format string, a ...any) {
-	if !viper.GetBool("quiet") {
+	if !viper.GetBool("quiet") && !IsJSON() {
 		log.New(os.Stdout, "", 0).Printf(format, a...)
 	}
 }
+
+// IsJSON returns true if machine-readable JSON output was requested.
+func IsJSON() bool {
+	return viper.GetString("output") == "json"
+}
+
+// JSON prints the data as JSON in JSON output mode. It does nothing in text output mode.
+func JSON(data any) {
+	if !IsJSON() {
+		return
+	}
+	bytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		Fatal("could not encode output: %s", err)
+	}
+	log.New(os.Stdout, "", 0).Print(string(bytes))
+}
+
+// Summary prints the number of operations per result, e.g. "3 started, 1 failed, 1 skipped".
+func Summary(results []Result) {
+	if len(results) == 0 {
+		return
+	}
+	var names []string
+	counts := make(map[string]int)
+	for _, result := range results {
+		if _, ok := counts[result.Result]; !ok {
+			names = append(names, result.Result)
+		}
+		counts[result.Result]++
+	}
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[name], name))
+	}
+	Info("%s.", strings.Join(parts, ", "))
+}
+
+// ExitOnFailure exits with ExitCodeFailure if any of the results failed.
+func ExitOnFailure(results []Result) {
+	for _, result := range results {
+		if result.Failed() {
+			os.Exit(ExitCodeFailure)
+		}
+	}
+}